@@ -2,14 +2,26 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/rohansx/vibeguard.io/internal/baseline"
+	"github.com/rohansx/vibeguard.io/internal/detection"
+	"github.com/rohansx/vibeguard.io/internal/policy"
+	"github.com/rohansx/vibeguard.io/internal/progress"
+	"github.com/rohansx/vibeguard.io/internal/reports"
+	"github.com/rohansx/vibeguard.io/internal/sarif"
 )
 
 const (
@@ -72,6 +84,14 @@ func main() {
 		cmdAnalyze(args)
 	case "init":
 		cmdInit(args)
+	case "policy":
+		cmdPolicy(args)
+	case "hook":
+		cmdHook(args)
+	case "baseline":
+		cmdBaseline(args)
+	case "report":
+		cmdReport(args)
 	case "version", "--version", "-v":
 		fmt.Printf("vibeguard version %s\n", version)
 	case "help", "--help", "-h":
@@ -93,22 +113,64 @@ COMMANDS:
     scan      Scan files or directories for AI-generated code
     analyze   Analyze a single file
     init      Initialize vibeguard.yaml in current directory
+    policy    Compile and test vibeguard.yaml policies
+    hook      Install or run git pre-commit/pre-push scan hooks
+    report    Fetch or diff historical scan reports from S3/MinIO storage
+    baseline  Record a drift baseline so scans only flag new AI content
     version   Print version information
     help      Show this help message
 
 SCAN OPTIONS:
     --path, -p <path>     Path to scan (default: current directory)
     --config, -c <file>   Path to vibeguard.yaml config
-    --format, -f <fmt>    Output format: text, json, github (default: text)
-    --local               Use local Python analyzer (no API)
+    --format, -f <fmt>    Output format: text, json, sarif, github (default: text)
+    --local               Use local analyzer (no API)
+    --engine <name>       Detection engine: go, python (default: go)
+    --since <ref>         Only scan files changed since <ref> (git diff)
+    --jobs, -j <n>        Concurrent analysis workers (default: NumCPU)
     --fail-on-block       Exit with code 1 if blocked
+    --baseline [file]     Only report files that drifted past a recorded
+                          baseline (default: .vibeguard/baseline.json)
+    --baseline-delta <n>  Minimum confidence increase to report, 0-1
+                          (default: 0.15)
+
+BASELINE OPTIONS:
+    baseline create        Record current AI confidence for every scanned
+                            file into .vibeguard/baseline.json
+        --path, -p <path>      Path to scan (default: current directory)
+        --output, -o <file>    Where to write the baseline (default:
+                                .vibeguard/baseline.json)
+
+HOOK OPTIONS:
+    hook install          Install a git hook that runs vibeguard hook run
+        --stage <stage>       pre-commit or pre-push (default: pre-commit)
+    hook run               Scan the staged or pushed diff; honors the
+                            block_on_commit policy action
+
+REPORT OPTIONS:
+    report fetch <commit>       Print the stored report.json for a commit
+    report diff <a> <b>         Show how AI-content/violations changed
+    (requires a storage: block in vibeguard.yaml, see init)
+
+POLICY OPTIONS:
+    policy test           Evaluate vibeguard.yaml against a synthetic context
+        --config, -c <file>    Path to vibeguard.yaml (default: ./vibeguard.yaml)
+        --ai-confidence <n>    Synthetic ai_confidence, 0-1
+        --path <p>             Synthetic path
+        --lines-changed <n>    Synthetic lines_changed
+        --review-time <n>      Synthetic review_time, minutes
+        --author-trust <n>     Synthetic author_trust, 0-1
 
 EXAMPLES:
     vibeguard scan
     vibeguard scan --path ./src
     vibeguard scan --format json
     vibeguard analyze src/auth/login.ts
-    vibeguard init`)
+    vibeguard init
+    vibeguard policy test --ai-confidence 0.8 --path src/auth/login.ts --author-trust 0.2
+    vibeguard report diff HEAD~10 HEAD
+    vibeguard baseline create
+    vibeguard scan --baseline`)
 }
 
 func cmdScan(args []string) {
@@ -117,6 +179,12 @@ func cmdScan(args []string) {
 	local := true // Default to local for now
 	failOnBlock := false
 	configPath := ""
+	engine := "go"
+	since := ""
+	jobs := runtime.NumCPU()
+	useBaseline := false
+	baselinePath := baseline.DefaultPath
+	deltaThreshold := baseline.DefaultDelta
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -139,11 +207,62 @@ func cmdScan(args []string) {
 			local = true
 		case "--fail-on-block":
 			failOnBlock = true
+		case "--engine":
+			if i+1 < len(args) {
+				engine = args[i+1]
+				i++
+			}
+		case "--since":
+			if i+1 < len(args) {
+				since = args[i+1]
+				i++
+			}
+		case "--jobs", "-j":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%d", &jobs)
+				i++
+			}
+		case "--baseline":
+			useBaseline = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				baselinePath = args[i+1]
+				i++
+			}
+		case "--baseline-delta":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%f", &deltaThreshold)
+				i++
+			}
 		}
 	}
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
 
-	// Find files to scan
-	files, err := findFiles(path)
+	var bl *baseline.Baseline
+	if useBaseline {
+		data, err := os.ReadFile(baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading baseline %s: %v (run `vibeguard baseline create` first)\n", baselinePath, err)
+			os.Exit(1)
+		}
+		bl, err = baseline.Load(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Find files to scan: either everything under path, or (with --since)
+	// only files that changed between <since> and the working tree, so CI
+	// can gate a commit/PR by scanning just what changed.
+	var files []string
+	var err error
+	if since != "" {
+		files, err = filesSinceRef(since)
+	} else {
+		files, err = findFiles(path)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding files: %v\n", err)
 		os.Exit(1)
@@ -161,10 +280,21 @@ func cmdScan(args []string) {
 		configPath = filepath.Join(path, "vibeguard.yaml")
 	}
 
+	// Showing a progress bar only makes sense for an interactive text
+	// report; machine-readable formats and non-TTY output go straight to
+	// stderr/stdout without a redrawing line in the middle of them.
+	showProgress := format == "text" && isTerminal(os.Stdout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	var result *ScanResult
-	if local {
-		result, err = scanLocal(files, configPath)
-	} else {
+	switch {
+	case local && engine == "python":
+		result, err = scanLocalPython(files, configPath)
+	case local:
+		result, err = scanLocalGo(ctx, files, configPath, jobs, showProgress, bl, deltaThreshold)
+	default:
 		result, err = scanRemote(files, configPath)
 	}
 
@@ -177,37 +307,284 @@ func cmdScan(args []string) {
 	switch format {
 	case "json":
 		outputJSON(result)
+	case "sarif":
+		outputSARIF(result)
 	case "github":
 		outputGitHub(result)
 	default:
 		outputText(result)
 	}
 
+	maybeUploadReport(configPath, result)
+
 	if failOnBlock && result.Blocked {
 		os.Exit(1)
 	}
 }
 
-func cmdAnalyze(args []string) {
+// maybeUploadReport uploads result to the bucket configured under
+// vibeguard.yaml's storage: block. It is a no-op (not an error) when no
+// storage is configured, since most scans don't use report retention.
+func maybeUploadReport(configPath string, result *ScanResult) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return
+	}
+
+	storageCfg, err := reports.LoadConfig(data)
+	if err != nil || !storageCfg.Enabled() {
+		return
+	}
+
+	client, err := reports.NewClient(storageCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vibeguard: report upload skipped: %v\n", err)
+		return
+	}
+
+	org, repo := repoIdentity()
+	commit, err := gitCommitSHA()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vibeguard: report upload skipped: %v\n", err)
+		return
+	}
+
+	report := toReportsScanReport(result)
+	sarifJSON, _ := json.Marshal(sarif.ToSARIF(toSARIFScanResult(result)))
+	html := renderHTMLSummary(result)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := client.Upload(ctx, org, repo, commit, report, sarifJSON, html); err != nil {
+		fmt.Fprintf(os.Stderr, "vibeguard: report upload failed: %v\n", err)
+	}
+}
+
+func toReportsScanReport(result *ScanResult) *reports.ScanReport {
+	files := make([]reports.FileResult, 0, len(result.Results))
+	for _, f := range result.Results {
+		files = append(files, reports.FileResult{Path: f.Path, AIConfidence: f.AIConfidence, Status: f.Status})
+	}
+	violations := make([]reports.Violation, 0, len(result.Violations))
+	for _, v := range result.Violations {
+		violations = append(violations, reports.Violation{Policy: v.Policy, Message: v.Message, Files: v.Files})
+	}
+	return &reports.ScanReport{
+		FilesScanned:    result.FilesScanned,
+		AIDetected:      result.AIDetected,
+		MaxAIConfidence: result.MaxAIConfidence,
+		AIPercentage:    result.AIPercentage,
+		Results:         files,
+		Blocked:         result.Blocked,
+		Violations:      violations,
+	}
+}
+
+func renderHTMLSummary(result *ScanResult) []byte {
+	var sb strings.Builder
+	sb.WriteString("<!doctype html><html><head><meta charset=\"utf-8\"><title>VibeGuard Report</title></head><body>\n")
+	sb.WriteString("<h1>VibeGuard Analysis</h1>\n")
+	fmt.Fprintf(&sb, "<p>Files scanned: %d &middot; AI-generated: %d &middot; AI percentage: %.1f%%</p>\n",
+		result.FilesScanned, result.AIDetected, result.AIPercentage)
+	if result.Blocked {
+		sb.WriteString("<p><strong>BLOCKED</strong> - policy violations detected</p>\n")
+	}
+	sb.WriteString("<table border=\"1\" cellpadding=\"4\"><tr><th>File</th><th>AI Confidence</th></tr>\n")
+	for _, f := range result.Results {
+		fmt.Fprintf(&sb, "<tr><td>%s</td><td>%.0f%%</td></tr>\n", f.Path, f.AIConfidence*100)
+	}
+	sb.WriteString("</table></body></html>\n")
+	return []byte(sb.String())
+}
+
+// repoIdentity derives the org/repo pair used as the S3 object key prefix
+// from the origin remote, falling back to the working directory name when
+// there's no remote (e.g. a scan run outside a clone).
+func repoIdentity() (org, repo string) {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err == nil {
+		url := strings.TrimSuffix(strings.TrimSpace(string(out)), ".git")
+		if idx := strings.Index(url, "@"); idx != -1 {
+			url = url[idx+1:]
+			url = strings.Replace(url, ":", "/", 1)
+		} else if idx := strings.Index(url, "://"); idx != -1 {
+			url = url[idx+3:]
+		}
+		parts := strings.Split(url, "/")
+		if len(parts) >= 2 {
+			return parts[len(parts)-2], parts[len(parts)-1]
+		}
+	}
+
+	cwd, _ := os.Getwd()
+	return "unknown-org", filepath.Base(cwd)
+}
+
+func gitCommitSHA() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func cmdReport(args []string) {
 	if len(args) == 0 {
-		fmt.Println("Usage: vibeguard analyze <file>")
+		fmt.Println("Usage: vibeguard report <fetch|diff> [options]")
 		os.Exit(1)
 	}
 
-	filePath := args[0]
-	content, err := os.ReadFile(filePath)
+	switch args[0] {
+	case "fetch":
+		cmdReportFetch(args[1:])
+	case "diff":
+		cmdReportDiff(args[1:])
+	default:
+		fmt.Printf("Unknown report subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func reportClient(configPath string) *reports.Client {
+	data, err := os.ReadFile(configPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", configPath, err)
+		os.Exit(1)
+	}
+
+	storageCfg, err := reports.LoadConfig(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing storage config: %v\n", err)
+		os.Exit(1)
+	}
+	if !storageCfg.Enabled() {
+		fmt.Fprintln(os.Stderr, "No storage: block configured in vibeguard.yaml")
+		os.Exit(1)
+	}
+
+	client, err := reports.NewClient(storageCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error connecting to report storage: %v\n", err)
+		os.Exit(1)
+	}
+	return client
+}
+
+func cmdReportFetch(args []string) {
+	configPath := "vibeguard.yaml"
+	var commit string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--config" || args[i] == "-c" {
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+			continue
+		}
+		if commit == "" {
+			commit = args[i]
+		}
+	}
+	if commit == "" {
+		fmt.Println("Usage: vibeguard report fetch <commit> [--config <file>]")
+		os.Exit(1)
+	}
+
+	client := reportClient(configPath)
+	org, repo := repoIdentity()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	report, err := client.Fetch(ctx, org, repo, commit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching report: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+}
+
+func cmdReportDiff(args []string) {
+	configPath := "vibeguard.yaml"
+	var refs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--config" || args[i] == "-c" {
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+			continue
+		}
+		refs = append(refs, args[i])
+	}
+	if len(refs) != 2 {
+		fmt.Println("Usage: vibeguard report diff <a> <b> [--config <file>]")
+		os.Exit(1)
+	}
+
+	client := reportClient(configPath)
+	org, repo := repoIdentity()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	diff, err := client.Diff(ctx, org, repo, refs[0], refs[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error diffing reports: %v\n", err)
 		os.Exit(1)
 	}
 
-	result, err := analyzeCode(string(content), filePath)
+	output, _ := json.MarshalIndent(diff, "", "  ")
+	fmt.Println(string(output))
+}
+
+func cmdAnalyze(args []string) {
+	engine := "go"
+	var filePath string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--engine":
+			if i+1 < len(args) {
+				engine = args[i+1]
+				i++
+			}
+		default:
+			if filePath == "" {
+				filePath = args[i]
+			}
+		}
+	}
+	if filePath == "" {
+		fmt.Println("Usage: vibeguard analyze <file> [--engine go|python]")
+		os.Exit(1)
+	}
+
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error analyzing: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
 		os.Exit(1)
 	}
 
-	conf := result["ai_probability"].(float64) * 100
+	var conf float64
+	if engine == "python" {
+		result, err := analyzeCodePython(string(content), filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing: %v\n", err)
+			os.Exit(1)
+		}
+		conf = result["ai_probability"].(float64) * 100
+	} else {
+		score, err := detection.AnalyzeCode(string(content), detection.LanguageFromExtension(filepath.Ext(filePath)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error analyzing: %v\n", err)
+			os.Exit(1)
+		}
+		conf = score.Probability * 100
+	}
+
 	status := "human-written"
 	statusIcon := "✓"
 	if conf > 70 {
@@ -235,6 +612,16 @@ func cmdInit(args []string) {
 version: "1.0"
 org: my-org
 
+# Uncomment to upload every scan's report.json/report.sarif.json/report.html
+# to an S3-compatible bucket (AWS S3, MinIO, R2, GCS). Credentials can also
+# come from VIBEGUARD_S3_* environment variables instead of this file.
+# storage:
+#   endpoint: s3.amazonaws.com
+#   bucket: my-org-vibeguard-reports
+#   access_key: ""
+#   secret_key: ""
+#   use_ssl: true
+
 policies:
   # Block AI code in authentication
   - name: no-ai-in-auth
@@ -266,6 +653,27 @@ policies:
       ai_percentage: "> 30%"
     action: warn
     message: "This PR was approved quickly. Please verify AI-generated sections."
+
+  # Expression-based trigger: combine signals with && / || and path globs.
+  # author_trust/review_time/language have no real producer yet (see
+  # EvalContext's doc comment in internal/policy/ast.go) -- vibeguard
+  # policy test can exercise them synthetically, but don't build a shipped
+  # policy around them until something populates them for real scans.
+  - name: ai-auth-change
+    description: "Sizeable AI-generated changes to auth need a human review"
+    when: 'ai_confidence > 0.6 && path matches "src/auth/**" && lines_changed > 20'
+    action: require_reviewers
+    reviewers:
+      teams: ["senior-engineers"]
+
+  # Drift trigger: only fires on scans run with --baseline (see
+  # vibeguard baseline create), once ai_confidence_delta is populated
+  # against the committed .vibeguard/baseline.json
+  - name: rising-ai-content
+    description: "Flag files whose AI content grew well past the baseline"
+    when: "ai_confidence_delta > 0.2"
+    action: warn
+    message: "This file's AI-generated content increased significantly since the baseline was recorded."
 `
 
 	err := os.WriteFile(configPath, []byte(config), 0644)
@@ -278,16 +686,370 @@ policies:
 	fmt.Println("\nEdit this file to customize your AI code policies.")
 }
 
-func findFiles(root string) ([]string, error) {
-	var files []string
+func cmdPolicy(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: vibeguard policy <test> [options]")
+		os.Exit(1)
+	}
 
-	// File extensions to scan
-	extensions := map[string]bool{
-		".ts": true, ".tsx": true, ".js": true, ".jsx": true,
-		".py": true, ".go": true, ".java": true, ".kt": true,
-		".rs": true, ".cpp": true, ".c": true, ".h": true,
-		".rb": true, ".php": true, ".swift": true, ".cs": true,
+	switch args[0] {
+	case "test":
+		cmdPolicyTest(args[1:])
+	default:
+		fmt.Printf("Unknown policy subcommand: %s\n", args[0])
+		os.Exit(1)
 	}
+}
+
+// cmdPolicyTest compiles vibeguard.yaml and evaluates it against a synthetic
+// EvalContext built from flags, so authors can see which policies fire
+// without needing a real scan or PR.
+func cmdPolicyTest(args []string) {
+	configPath := "vibeguard.yaml"
+	ctx := policy.EvalContext{}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--config", "-c":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		case "--ai-confidence":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%f", &ctx.AIConfidence)
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				ctx.Path = args[i+1]
+				i++
+			}
+		case "--lines-changed":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%d", &ctx.LinesChanged)
+				i++
+			}
+		case "--review-time":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%f", &ctx.ReviewTime)
+				i++
+			}
+		case "--author-trust":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%f", &ctx.AuthorTrust)
+				i++
+			}
+		}
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", configPath, err)
+		os.Exit(1)
+	}
+
+	ruleSet, err := policy.Compile(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error compiling policies: %v\n", err)
+		os.Exit(1)
+	}
+
+	decisions := ruleSet.Evaluate(ctx)
+	if len(decisions) == 0 {
+		fmt.Println("No policies matched this context.")
+		return
+	}
+
+	fmt.Printf("%d polic%s matched:\n\n", len(decisions), pluralSuffix(len(decisions)))
+	for _, d := range decisions {
+		fmt.Printf("  [%s] %s\n", d.Action, d.Policy)
+		if d.Message != "" {
+			fmt.Printf("      %s\n", d.Message)
+		}
+	}
+}
+
+func pluralSuffix(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// scanExtensions are the file extensions findFiles and filesSinceRef
+// consider scannable.
+var scanExtensions = map[string]bool{
+	".ts": true, ".tsx": true, ".js": true, ".jsx": true,
+	".py": true, ".go": true, ".java": true, ".kt": true,
+	".rs": true, ".cpp": true, ".c": true, ".h": true,
+	".rb": true, ".php": true, ".swift": true, ".cs": true,
+}
+
+func cmdHook(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: vibeguard hook <install|run> [options]")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "install":
+		cmdHookInstall(args[1:])
+	case "run":
+		cmdHookRun(args[1:])
+	default:
+		fmt.Printf("Unknown hook subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func cmdHookInstall(args []string) {
+	stage := "pre-commit"
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--stage" && i+1 < len(args) {
+			stage = args[i+1]
+			i++
+		}
+	}
+	if stage != "pre-commit" && stage != "pre-push" {
+		fmt.Fprintf(os.Stderr, "Unsupported stage: %s (expected pre-commit or pre-push)\n", stage)
+		os.Exit(1)
+	}
+
+	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locating .git directory: %v\n", err)
+		os.Exit(1)
+	}
+	gitDir := strings.TrimSpace(string(out))
+
+	hooksDir := filepath.Join(gitDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating hooks directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	hookPath := filepath.Join(hooksDir, stage)
+	script := fmt.Sprintf("#!/bin/sh\nexec vibeguard hook run --stage %s \"$@\"\n", stage)
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing hook: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Installed %s hook at %s\n", stage, hookPath)
+}
+
+// hookFile is a file and its blob content as seen by git at the relevant
+// point in the hook (the index for pre-commit, the pushed commit for
+// pre-push) -- never the working tree, so uncommitted edits can't sneak
+// past the hook.
+type hookFile struct {
+	Path    string
+	Content string
+}
+
+func cmdHookRun(args []string) {
+	stage := "pre-commit"
+	configPath := "vibeguard.yaml"
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--stage":
+			if i+1 < len(args) {
+				stage = args[i+1]
+				i++
+			}
+		case "--config", "-c":
+			if i+1 < len(args) {
+				configPath = args[i+1]
+				i++
+			}
+		}
+	}
+
+	var files []hookFile
+	var err error
+	switch stage {
+	case "pre-push":
+		files, err = prePushFiles(os.Stdin)
+	default:
+		files, err = stagedFiles()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error collecting changed files: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(files) == 0 {
+		os.Exit(0)
+	}
+
+	entries := make([]contentEntry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, contentEntry{Path: f.Path, Content: f.Content})
+	}
+
+	result, err := analyzeContents(entries, configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputText(result)
+
+	if result.Blocked {
+		fmt.Fprintln(os.Stderr, "\nvibeguard: blocked by a block or block_on_commit policy, aborting")
+		os.Exit(1)
+	}
+}
+
+func cmdBaseline(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: vibeguard baseline <create> [options]")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "create":
+		cmdBaselineCreate(args[1:])
+	default:
+		fmt.Printf("Unknown baseline subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// cmdBaselineCreate scans path and records every file's current AI
+// confidence into a baseline, committed to the repo so later `scan
+// --baseline` runs can diff against it instead of re-flagging the same
+// AI-heavy code on every commit.
+func cmdBaselineCreate(args []string) {
+	path := "."
+	outputPath := baseline.DefaultPath
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--path", "-p":
+			if i+1 < len(args) {
+				path = args[i+1]
+				i++
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputPath = args[i+1]
+				i++
+			}
+		}
+	}
+
+	files, err := findFiles(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding files: %v\n", err)
+		os.Exit(1)
+	}
+
+	bl := baseline.New()
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		score, err := detection.AnalyzeCode(string(content), detection.LanguageFromExtension(filepath.Ext(f)))
+		if err != nil {
+			continue
+		}
+		bl.Set(f, score.Probability)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", filepath.Dir(outputPath), err)
+		os.Exit(1)
+	}
+
+	data, err := bl.Save()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error serializing baseline: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputPath, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Recorded baseline for %d files in %s\n", len(bl.Files), outputPath)
+	fmt.Println("Commit this file, then use `vibeguard scan --baseline` to flag only new AI-generated code.")
+}
+
+// stagedFiles reads the staged (index) content of every added/copied/
+// modified file, so the hook scans exactly what's about to be committed.
+func stagedFiles() ([]hookFile, error) {
+	out, err := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACM").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached: %w", err)
+	}
+
+	var files []hookFile
+	for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if path == "" || !scanExtensions[filepath.Ext(path)] {
+			continue
+		}
+		content, err := exec.Command("git", "show", ":"+path).Output()
+		if err != nil {
+			continue
+		}
+		files = append(files, hookFile{Path: path, Content: string(content)})
+	}
+	return files, nil
+}
+
+// prePushFiles implements git's pre-push hook protocol: each line on stdin
+// is "<local ref> <local sha1> <remote ref> <remote sha1>". It diffs the
+// range being pushed and reads each file's content from the local sha1
+// being pushed, not the working tree.
+func prePushFiles(stdin io.Reader) ([]hookFile, error) {
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading pre-push stdin: %w", err)
+	}
+
+	var files []hookFile
+	seen := map[string]bool{}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		localSHA, remoteSHA := fields[1], fields[3]
+		if localSHA == strings.Repeat("0", 40) {
+			continue // branch deletion, nothing to scan
+		}
+
+		diffRange := localSHA
+		if remoteSHA != strings.Repeat("0", 40) {
+			diffRange = remoteSHA + ".." + localSHA
+		}
+
+		out, err := exec.Command("git", "diff", "--name-only", "--diff-filter=ACM", diffRange).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git diff %s: %w", diffRange, err)
+		}
+
+		for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if path == "" || seen[path] || !scanExtensions[filepath.Ext(path)] {
+				continue
+			}
+			content, err := exec.Command("git", "show", localSHA+":"+path).Output()
+			if err != nil {
+				continue
+			}
+			seen[path] = true
+			files = append(files, hookFile{Path: path, Content: string(content)})
+		}
+	}
+	return files, nil
+}
+
+func findFiles(root string) ([]string, error) {
+	var files []string
 
 	// Directories to skip
 	skipDirs := map[string]bool{
@@ -309,7 +1071,7 @@ func findFiles(root string) ([]string, error) {
 		}
 
 		ext := filepath.Ext(path)
-		if extensions[ext] {
+		if scanExtensions[ext] {
 			files = append(files, path)
 		}
 
@@ -319,7 +1081,361 @@ func findFiles(root string) ([]string, error) {
 	return files, err
 }
 
-func scanLocal(files []string, configPath string) (*ScanResult, error) {
+// filesSinceRef returns the paths, relative to the repo root, that changed
+// between since and the working tree, filtered to scanExtensions. Deleted
+// files are skipped since there's nothing left on disk to scan.
+func filesSinceRef(since string) ([]string, error) {
+	out, err := exec.Command("git", "diff", "--name-only", "--diff-filter=ACM", since).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --since %s: %w", since, err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if path == "" {
+			continue
+		}
+		if !scanExtensions[filepath.Ext(path)] {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// isTerminal reports whether f is attached to a terminal, used to
+// auto-disable the progress bar when output is piped or redirected.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// fileAnalysisResult is one worker's output in scanLocalGo's pipeline.
+type fileAnalysisResult struct {
+	path  string
+	score detection.AIScore
+	lines int
+}
+
+// scanLocalGo analyzes files with the native Go detection and policy
+// packages. This is the default engine: it has no filesystem or interpreter
+// assumptions, so the binary works standalone. Analysis runs across a
+// worker pool of size jobs; ctx cancellation (e.g. SIGINT) stops in-flight
+// work and the function returns whatever results completed so far.
+//
+// bl is nil unless --baseline was passed; when set, driftFilter narrows the
+// result down to files whose AI confidence drifted past deltaThreshold
+// since the baseline was recorded, per internal/baseline's drift model.
+func scanLocalGo(ctx context.Context, files []string, configPath string, jobs int, showProgress bool, bl *baseline.Baseline, deltaThreshold float64) (*ScanResult, error) {
+	paths := make(chan string)
+	results := make(chan fileAnalysisResult)
+
+	var bar *progress.Bar
+	if showProgress {
+		bar = progress.New(os.Stdout, len(files))
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(jobs)
+	for i := 0; i < jobs; i++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					continue
+				}
+				score, err := detection.AnalyzeCode(string(content), detection.LanguageFromExtension(filepath.Ext(path)))
+				if err != nil {
+					continue
+				}
+				select {
+				case results <- fileAnalysisResult{path: path, score: score, lines: strings.Count(string(content), "\n") + 1}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(paths)
+		for _, f := range files {
+			select {
+			case paths <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var fileAnalyses []policy.FileAnalysis
+	var fileResults []FileResult
+	maxAI := 0.0
+	totalAILines, totalLines, aiDetected, humanWritten := 0, 0, 0, 0
+
+	for r := range results {
+		if bar != nil {
+			bar.Increment()
+		}
+
+		if r.score.Probability > maxAI {
+			maxAI = r.score.Probability
+		}
+
+		status := "human-written"
+		if r.score.Probability > 0.7 {
+			status = "ai-generated"
+			totalAILines += r.lines
+			aiDetected++
+		} else {
+			humanWritten++
+		}
+		totalLines += r.lines
+
+		fileAnalyses = append(fileAnalyses, policy.FileAnalysis{
+			Path:         r.path,
+			AIConfidence: r.score.Probability,
+			LinesChanged: r.lines,
+			Status:       status,
+		})
+		fileResults = append(fileResults, FileResult{
+			Path:         r.path,
+			AIConfidence: r.score.Probability,
+			Status:       status,
+		})
+	}
+
+	if bar != nil {
+		bar.Finish()
+	}
+
+	status := "completed"
+	if ctx.Err() != nil {
+		status = "cancelled"
+		fmt.Fprintln(os.Stderr, "vibeguard: scan cancelled, reporting partial results")
+	}
+
+	maxDelta := 0.0
+	if bl != nil {
+		fileAnalyses, fileResults, maxDelta = driftFilter(fileAnalyses, fileResults, bl, deltaThreshold)
+		totalAILines, totalLines, aiDetected, humanWritten = 0, 0, 0, 0
+		for _, r := range fileResults {
+			if r.Status == "ai-generated" {
+				aiDetected++
+			} else {
+				humanWritten++
+			}
+		}
+		for _, fa := range fileAnalyses {
+			totalLines += fa.LinesChanged
+			if fa.Status == "ai-generated" {
+				totalAILines += fa.LinesChanged
+			}
+		}
+	}
+
+	aiPct := 0.0
+	if totalLines > 0 {
+		aiPct = float64(totalAILines) / float64(totalLines) * 100
+	}
+
+	cfg, err := loadPolicyConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	policyResult, err := policy.Evaluate(cfg, &policy.Analysis{
+		Files:                fileAnalyses,
+		MaxAIConfidence:      maxAI,
+		MaxAIConfidenceDelta: maxDelta,
+		AIPercentage:         aiPct,
+		TotalLinesChanged:    totalLines,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation: %w", err)
+	}
+
+	return &ScanResult{
+		Status:          status,
+		FilesScanned:    len(fileResults),
+		AIDetected:      aiDetected,
+		HumanWritten:    humanWritten,
+		MaxAIConfidence: maxAI,
+		AIPercentage:    aiPct,
+		Results:         fileResults,
+		Blocked:         !policyResult.Allowed,
+		Violations:      toCLIViolations(policyResult.Violations),
+		Warnings:        toCLIWarnings(policyResult.Warnings),
+	}, nil
+}
+
+// driftFilter narrows analyses/results down to files whose AI confidence
+// rose by at least deltaThreshold since bl was recorded, or that are new to
+// the tree and already above the standard AI-generated threshold. It
+// returns the max delta seen across every file (not just the ones kept),
+// so a repo-wide `ai_confidence_delta` policy trigger can still fire even
+// when no single file's report would otherwise be shown.
+func driftFilter(analyses []policy.FileAnalysis, results []FileResult, bl *baseline.Baseline, deltaThreshold float64) ([]policy.FileAnalysis, []FileResult, float64) {
+	var keptAnalyses []policy.FileAnalysis
+	var keptResults []FileResult
+	maxDelta := 0.0
+
+	for i := range analyses {
+		delta, isNew := bl.Delta(analyses[i].Path, analyses[i].AIConfidence)
+		analyses[i].AIConfidenceDelta = delta
+		if delta > maxDelta {
+			maxDelta = delta
+		}
+
+		drifted := (isNew && analyses[i].AIConfidence > 0.7) || (!isNew && delta >= deltaThreshold)
+		if !drifted {
+			continue
+		}
+		keptAnalyses = append(keptAnalyses, analyses[i])
+		keptResults = append(keptResults, results[i])
+	}
+
+	return keptAnalyses, keptResults, maxDelta
+}
+
+// contentEntry is a file path paired with its content, used so the scan
+// pipeline can run over in-memory content (e.g. git blobs read by the hook
+// subcommand) as easily as files on disk.
+type contentEntry struct {
+	Path    string
+	Content string
+}
+
+// analyzeContents is the Go-engine pipeline behind the hook subcommand: run
+// detection.AnalyzeCode over every entry, then evaluate the loaded policy
+// config against the aggregate analysis. scanLocalGo does not call it; it
+// runs the same steps over files on disk with its own worker pool.
+func analyzeContents(entries []contentEntry, configPath string) (*ScanResult, error) {
+	var fileAnalyses []policy.FileAnalysis
+	var results []FileResult
+	maxAI := 0.0
+	totalAILines := 0
+	totalLines := 0
+	aiDetected := 0
+	humanWritten := 0
+
+	for _, e := range entries {
+		score, err := detection.AnalyzeCode(e.Content, detection.LanguageFromExtension(filepath.Ext(e.Path)))
+		if err != nil {
+			continue
+		}
+
+		lines := strings.Count(e.Content, "\n") + 1
+		if score.Probability > maxAI {
+			maxAI = score.Probability
+		}
+		if score.Probability > 0.7 {
+			totalAILines += lines
+			aiDetected++
+		} else {
+			humanWritten++
+		}
+		totalLines += lines
+
+		status := "human-written"
+		if score.Probability > 0.7 {
+			status = "ai-generated"
+		}
+
+		fileAnalyses = append(fileAnalyses, policy.FileAnalysis{
+			Path:         e.Path,
+			AIConfidence: score.Probability,
+			LinesChanged: lines,
+			Status:       status,
+		})
+		results = append(results, FileResult{
+			Path:         e.Path,
+			AIConfidence: score.Probability,
+			Status:       status,
+		})
+	}
+
+	aiPct := 0.0
+	if totalLines > 0 {
+		aiPct = float64(totalAILines) / float64(totalLines) * 100
+	}
+
+	cfg, err := loadPolicyConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	policyResult, err := policy.Evaluate(cfg, &policy.Analysis{
+		Files:             fileAnalyses,
+		MaxAIConfidence:   maxAI,
+		AIPercentage:      aiPct,
+		TotalLinesChanged: totalLines,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation: %w", err)
+	}
+
+	return &ScanResult{
+		Status:          "completed",
+		FilesScanned:    len(results),
+		AIDetected:      aiDetected,
+		HumanWritten:    humanWritten,
+		MaxAIConfidence: maxAI,
+		AIPercentage:    aiPct,
+		Results:         results,
+		Blocked:         !policyResult.Allowed,
+		Violations:      toCLIViolations(policyResult.Violations),
+		Warnings:        toCLIWarnings(policyResult.Warnings),
+	}, nil
+}
+
+// loadPolicyConfig reads and parses vibeguard.yaml at configPath. A missing
+// file yields an empty ruleset rather than an error; unlike the Python
+// engine, which falls back to its EXAMPLE_CONFIG, no example policies are
+// applied.
+func loadPolicyConfig(configPath string) (*policy.Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return &policy.Config{}, nil
+	}
+	cfg, err := policy.LoadConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", configPath, err)
+	}
+	return cfg, nil
+}
+
+func toCLIViolations(vs []policy.Violation) []Violation {
+	out := make([]Violation, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, Violation{Policy: v.Policy, Message: v.Message, Files: v.Files})
+	}
+	return out
+}
+
+func toCLIWarnings(ws []policy.Warning) []Warning {
+	out := make([]Warning, 0, len(ws))
+	for _, w := range ws {
+		out = append(out, Warning{Policy: w.Policy, Message: w.Message})
+	}
+	return out
+}
+
+// scanLocalPython is the legacy engine, kept behind --engine python for
+// teams still relying on the Python stylometry/policy prototype.
+func scanLocalPython(files []string, configPath string) (*ScanResult, error) {
 	// Read file contents
 	var fileData []map[string]string
 	for _, f := range files {
@@ -466,7 +1582,8 @@ func scanRemote(files []string, configPath string) (*ScanResult, error) {
 	return &result, nil
 }
 
-func analyzeCode(code, filename string) (map[string]interface{}, error) {
+// analyzeCodePython is the legacy engine, kept behind --engine python.
+func analyzeCodePython(code, filename string) (map[string]interface{}, error) {
 	script := fmt.Sprintf(`
 import sys
 sys.path.insert(0, '/root/clawd/vibeguard')
@@ -554,6 +1671,31 @@ func outputJSON(result *ScanResult) {
 	fmt.Println(string(output))
 }
 
+func outputSARIF(result *ScanResult) {
+	report := sarif.ToSARIF(toSARIFScanResult(result))
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+}
+
+func toSARIFScanResult(result *ScanResult) *sarif.ScanResult {
+	files := make([]sarif.FileResult, 0, len(result.Results))
+	for _, f := range result.Results {
+		files = append(files, sarif.FileResult{Path: f.Path, AIConfidence: f.AIConfidence, Status: f.Status})
+	}
+
+	violations := make([]sarif.Violation, 0, len(result.Violations))
+	for _, v := range result.Violations {
+		violations = append(violations, sarif.Violation{Policy: v.Policy, Message: v.Message, Files: v.Files})
+	}
+
+	warnings := make([]sarif.Warning, 0, len(result.Warnings))
+	for _, w := range result.Warnings {
+		warnings = append(warnings, sarif.Warning{Policy: w.Policy, Message: w.Message})
+	}
+
+	return &sarif.ScanResult{Results: files, Violations: violations, Warnings: warnings}
+}
+
 func outputGitHub(result *ScanResult) {
 	// GitHub Actions output format
 	if result.Blocked {
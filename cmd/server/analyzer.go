@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rohansx/vibeguard.io/internal/detection"
+	"github.com/rohansx/vibeguard.io/internal/policy"
+)
+
+// Analyzer scores a PR's changed files for AI-generated content and
+// evaluates them against vibeguard.yaml. GoAnalyzer is the default,
+// self-contained implementation; PythonAnalyzer exists for teams still
+// running the legacy stylometry service during migration.
+type Analyzer interface {
+	Analyze(ctx context.Context, files []PRFile) (*AnalysisResult, error)
+}
+
+// GoAnalyzer runs internal/detection and internal/policy natively, the same
+// engine cmd/cli uses for `vibeguard scan --local`.
+type GoAnalyzer struct {
+	PolicyConfigPath string
+}
+
+// Analyze scores every file with content and evaluates the result against
+// the policy config at PolicyConfigPath. It respects ctx cancellation
+// between files, same as the CLI's worker-pool scanner.
+func (a *GoAnalyzer) Analyze(ctx context.Context, files []PRFile) (*AnalysisResult, error) {
+	var fileAnalyses []policy.FileAnalysis
+	var results []FileResult
+	maxAI := 0.0
+	totalAILines, totalLines, aiDetected := 0, 0, 0
+
+	for _, f := range files {
+		if f.Content == "" {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		lang := detection.LanguageFromExtension(filepath.Ext(f.Path))
+		score, err := detection.AnalyzeCode(f.Content, lang)
+		if err != nil {
+			continue
+		}
+		regions, err := detection.AnalyzeWindows(f.Content, lang, detection.DefaultWindowLines)
+		if err != nil {
+			regions = nil
+		}
+
+		lines := strings.Count(f.Content, "\n") + 1
+		status := "human-written"
+		if score.Probability > 0.7 {
+			status = "ai-generated"
+			aiDetected++
+			totalAILines += lines
+		}
+		totalLines += lines
+		if score.Probability > maxAI {
+			maxAI = score.Probability
+		}
+
+		fileAnalyses = append(fileAnalyses, policy.FileAnalysis{
+			Path:         f.Path,
+			AIConfidence: score.Probability,
+			LinesChanged: lines,
+			Status:       status,
+		})
+		results = append(results, FileResult{
+			Path:         f.Path,
+			AIConfidence: score.Probability,
+			LinesChanged: lines,
+			Status:       status,
+			Regions:      regions,
+		})
+	}
+
+	aiPct := 0.0
+	if totalLines > 0 {
+		aiPct = float64(totalAILines) / float64(totalLines) * 100
+	}
+
+	cfg, err := loadServerPolicyConfig(a.PolicyConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	policyResult, err := policy.Evaluate(cfg, &policy.Analysis{
+		Files:             fileAnalyses,
+		MaxAIConfidence:   maxAI,
+		AIPercentage:      aiPct,
+		TotalLinesChanged: totalLines,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation: %w", err)
+	}
+
+	violations := toServerViolations(policyResult.Violations)
+	warnings := toServerWarnings(policyResult.Warnings)
+
+	return &AnalysisResult{
+		FilesScanned:    len(results),
+		AIDetected:      aiDetected,
+		MaxAIConfidence: maxAI,
+		AIPercentage:    aiPct,
+		Results:         results,
+		PolicyEvaluation: PolicyResult{
+			Allowed:           policyResult.Allowed,
+			Violations:        violations,
+			Warnings:          warnings,
+			RequiredReviewers: policyResult.RequiredReviewers,
+		},
+		Blocked:    !policyResult.Allowed,
+		Violations: violations,
+		Warnings:   warnings,
+	}, nil
+}
+
+// loadServerPolicyConfig reads and parses vibeguard.yaml at path. A missing
+// file yields an empty ruleset rather than an error, matching cmd/cli's
+// loadPolicyConfig.
+func loadServerPolicyConfig(path string) (*policy.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &policy.Config{}, nil
+	}
+	cfg, err := policy.LoadConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func toServerViolations(vs []policy.Violation) []Violation {
+	out := make([]Violation, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, Violation{Policy: v.Policy, Message: v.Message, Files: v.Files})
+	}
+	return out
+}
+
+func toServerWarnings(ws []policy.Warning) []Warning {
+	out := make([]Warning, 0, len(ws))
+	for _, w := range ws {
+		out = append(out, Warning{Policy: w.Policy, Message: w.Message})
+	}
+	return out
+}
+
+// PythonAnalyzer proxies analysis to the legacy Python stylometry service.
+// Unlike the code it replaces, it never builds a Python script out of
+// request data: NewPythonAnalyzer starts `python3 -m api.server` once as a
+// long-lived subprocess, and Analyze talks to it over HTTP loopback with
+// the request body passed as-is, the same protocol analyzeFiles used to
+// simulate through the Flask test client.
+type PythonAnalyzer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPythonAnalyzer starts the Python service rooted at projectRoot,
+// listening on addr (e.g. "127.0.0.1:8801"), and returns a client for it.
+func NewPythonAnalyzer(pythonPath, projectRoot, addr string) (*PythonAnalyzer, error) {
+	cmd := exec.Command(pythonPath, "-m", "api.server")
+	cmd.Dir = projectRoot
+	cmd.Env = append(os.Environ(), "PORT="+addr)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting python analyzer: %w", err)
+	}
+
+	return &PythonAnalyzer{
+		baseURL: "http://" + addr,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Analyze posts files to the running Python service's /api/v1/scan
+// endpoint and decodes its response.
+func (a *PythonAnalyzer) Analyze(ctx context.Context, files []PRFile) (*AnalysisResult, error) {
+	var fileData []map[string]string
+	for _, f := range files {
+		if f.Content != "" {
+			fileData = append(fileData, map[string]string{"path": f.Path, "content": f.Content})
+		}
+	}
+
+	reqBody, err := json.Marshal(map[string]interface{}{"files": fileData})
+	if err != nil {
+		return nil, fmt.Errorf("python analyzer: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/api/v1/scan", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("python analyzer: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("python analyzer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result AnalysisResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("python analyzer: decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -10,18 +12,40 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/rohansx/vibeguard.io/internal/detection"
+	"github.com/rohansx/vibeguard.io/internal/github"
+	"github.com/rohansx/vibeguard.io/internal/queue"
+	"github.com/rohansx/vibeguard.io/internal/webhook"
 )
 
 // Config holds server configuration
 type Config struct {
-	Port              string
-	GitHubAppID       string
-	GitHubPrivateKey  string
+	Port                string
+	GitHubAppID         string
+	GitHubPrivateKey    string
 	GitHubWebhookSecret string
-	PythonPath        string
+	AnalyzerEngine      string // "go" (default) or "python"
+	PolicyConfigPath    string
+	PythonPath          string
+	PythonAddr          string
+	AnalysisTimeout     time.Duration
+	ShutdownTimeout     time.Duration
+	QueueDriver         string // "sqlite" (default); see internal/queue's package doc for why that's the only driver today
+	QueueDSN            string
+	QueueConcurrency    int
+	WebhookMaxBodyBytes int64
+	WebhookDedupeSize   int
+	WebhookDedupeTTL    time.Duration
 }
 
 // PREvent represents a GitHub pull request event
@@ -61,7 +85,7 @@ type PREvent struct {
 	} `json:"installation"`
 }
 
-// AnalysisResult from Python detection
+// AnalysisResult is the outcome of running an Analyzer over a PR's files.
 type AnalysisResult struct {
 	FilesScanned     int           `json:"files_scanned"`
 	AIDetected       int           `json:"ai_detected"`
@@ -75,10 +99,11 @@ type AnalysisResult struct {
 }
 
 type FileResult struct {
-	Path         string  `json:"path"`
-	AIConfidence float64 `json:"ai_confidence"`
-	LinesChanged int     `json:"lines_changed"`
-	Status       string  `json:"status"`
+	Path         string                `json:"path"`
+	AIConfidence float64               `json:"ai_confidence"`
+	LinesChanged int                   `json:"lines_changed"`
+	Status       string                `json:"status"`
+	Regions      []detection.LineRange `json:"regions,omitempty"`
 }
 
 type PolicyResult struct {
@@ -111,12 +136,95 @@ type CheckRun struct {
 }
 
 type CheckOutput struct {
-	Title   string `json:"title"`
-	Summary string `json:"summary"`
-	Text    string `json:"text,omitempty"`
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Text        string       `json:"text,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation is an inline Check Run annotation, mirroring the GitHub Checks
+// API shape (see internal/github's identical type).
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"` // notice, warning, failure
+	Message         string `json:"message"`
+	Title           string `json:"title,omitempty"`
+}
+
+// maxAnnotationsPerCheckRun is GitHub's limit on annotations in a single
+// Check Run create/update call; larger result sets must be split across
+// multiple UpdateCheckRun calls.
+const maxAnnotationsPerCheckRun = 50
+
+// buildAnnotations turns each file's per-window AI-confidence regions into
+// Check Run annotations: failure above 0.9, warning above 0.7, and nothing
+// below that, so a file with scattered low-confidence windows doesn't
+// drown the PR in noise.
+func buildAnnotations(results []FileResult) []Annotation {
+	var annotations []Annotation
+	for _, f := range results {
+		for _, region := range f.Regions {
+			var level string
+			switch {
+			case region.Probability > 0.9:
+				level = "failure"
+			case region.Probability > 0.7:
+				level = "warning"
+			default:
+				continue
+			}
+			annotations = append(annotations, Annotation{
+				Path:            f.Path,
+				StartLine:       region.StartLine,
+				EndLine:         region.EndLine,
+				AnnotationLevel: level,
+				Title:           "Possible AI-generated code",
+				Message:         fmt.Sprintf("%.0f%% confidence this region is AI-generated.", region.Probability*100),
+			})
+		}
+	}
+	return annotations
+}
+
+// batchAnnotations splits annotations into chunks of at most size, the
+// shape each UpdateCheckRun call can carry.
+func batchAnnotations(annotations []Annotation, size int) [][]Annotation {
+	if len(annotations) == 0 {
+		return nil
+	}
+	var batches [][]Annotation
+	for i := 0; i < len(annotations); i += size {
+		end := i + size
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		batches = append(batches, annotations[i:end])
+	}
+	return batches
 }
 
 var config Config
+var analyzer Analyzer
+var jobStore *queue.Store
+var deliveryCache *webhook.DeliveryCache
+
+// ghApp mints installation-scoped GitHub clients for createCheckRun,
+// completeCheckRun, and getPRFiles. It's nil when the server isn't
+// configured with a GitHub App (e.g. local/python-analyzer-only setups),
+// in which case those functions log instead of calling the GitHub API.
+var ghApp *github.App
+
+// rootCtx is cancelled on SIGINT/SIGTERM, which stops the worker pool from
+// claiming new jobs. Each job's analysis runs under its own AnalysisTimeout,
+// detached from rootCtx's cancellation, so jobs already running can finish
+// during a graceful shutdown.
+var rootCtx context.Context
+
+// workerWG tracks the job-queue worker pool so main can wait for in-flight
+// jobs to finish (or hit their timeout) during a graceful shutdown.
+var workerWG sync.WaitGroup
 
 func main() {
 	config = Config{
@@ -124,17 +232,92 @@ func main() {
 		GitHubAppID:         os.Getenv("GITHUB_APP_ID"),
 		GitHubPrivateKey:    os.Getenv("GITHUB_PRIVATE_KEY"),
 		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		AnalyzerEngine:      getEnv("ANALYZER_ENGINE", "go"),
+		PolicyConfigPath:    getEnv("VIBEGUARD_CONFIG", "vibeguard.yaml"),
 		PythonPath:          getEnv("PYTHON_PATH", "python3"),
+		PythonAddr:          getEnv("PYTHON_ANALYZER_ADDR", "127.0.0.1:8801"),
+		AnalysisTimeout:     getEnvDuration("ANALYSIS_TIMEOUT", 5*time.Minute),
+		ShutdownTimeout:     getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		QueueDriver:         getEnv("QUEUE_DRIVER", "sqlite"),
+		QueueDSN:            getEnv("QUEUE_DSN", "vibeguard-queue.db"),
+		QueueConcurrency:    getEnvInt("QUEUE_CONCURRENCY", 4),
+		WebhookMaxBodyBytes: int64(getEnvInt("WEBHOOK_MAX_BODY_BYTES", int(webhook.DefaultMaxBodySize))),
+		WebhookDedupeSize:   getEnvInt("WEBHOOK_DEDUPE_SIZE", webhook.DefaultCacheSize),
+		WebhookDedupeTTL:    getEnvDuration("WEBHOOK_DEDUPE_TTL", webhook.DefaultTTL),
 	}
 
-	http.HandleFunc("/", handleHealth)
-	http.HandleFunc("/api/health", handleHealth)
-	http.HandleFunc("/webhook/github", handleGitHubWebhook)
-	http.HandleFunc("/api/v1/analyze", handleAnalyze)
-	http.HandleFunc("/api/v1/scan", handleScan)
+	deliveryCache = webhook.NewDeliveryCache(config.WebhookDedupeSize, config.WebhookDedupeTTL)
 
-	log.Printf("VibeGuard server starting on port %s", config.Port)
-	log.Fatal(http.ListenAndServe(":"+config.Port, nil))
+	if config.GitHubAppID != "" && config.GitHubPrivateKey != "" {
+		app, err := github.NewApp(config.GitHubAppID, config.GitHubPrivateKey)
+		if err != nil {
+			log.Fatalf("configuring github app: %v", err)
+		}
+		ghApp = app
+	}
+
+	switch config.AnalyzerEngine {
+	case "python":
+		py, err := NewPythonAnalyzer(config.PythonPath, getProjectRoot(), config.PythonAddr)
+		if err != nil {
+			log.Fatalf("starting python analyzer: %v", err)
+		}
+		analyzer = py
+	default:
+		analyzer = &GoAnalyzer{PolicyConfigPath: config.PolicyConfigPath}
+	}
+
+	var stop context.CancelFunc
+	rootCtx, stop = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if config.QueueDriver != "sqlite" {
+		log.Fatalf("unsupported QUEUE_DRIVER %q: only \"sqlite\" is supported today", config.QueueDriver)
+	}
+	db, err := sql.Open(config.QueueDriver, config.QueueDSN)
+	if err != nil {
+		log.Fatalf("opening queue database: %v", err)
+	}
+	jobStore = queue.NewStore(db)
+	if err := jobStore.Migrate(rootCtx); err != nil {
+		log.Fatalf("migrating queue database: %v", err)
+	}
+
+	worker := queue.NewWorker(jobStore, processQueuedJob, config.QueueConcurrency)
+	workerWG.Add(1)
+	go func() {
+		defer workerWG.Done()
+		worker.Run(rootCtx)
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleHealth)
+	mux.HandleFunc("/api/health", handleHealth)
+	mux.HandleFunc("/webhook/github", handleGitHubWebhook)
+	mux.HandleFunc("/api/v1/analyze", handleAnalyze)
+	mux.HandleFunc("/api/v1/scan", handleScan)
+	mux.HandleFunc("/api/v1/jobs/", handleJobStatus)
+
+	srv := &http.Server{Addr: ":" + config.Port, Handler: mux}
+
+	go func() {
+		log.Printf("VibeGuard server starting on port %s (analyzer: %s, queue: %s)", config.Port, config.AnalyzerEngine, config.QueueDriver)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
+
+	<-rootCtx.Done()
+	log.Println("shutting down, waiting for in-flight analyses...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server shutdown: %v", err)
+	}
+
+	workerWG.Wait()
+	log.Println("shutdown complete")
 }
 
 func getEnv(key, fallback string) string {
@@ -144,6 +327,24 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -159,10 +360,12 @@ func handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Read body
+	// Read body, capped before HMAC computation so an unauthenticated POST
+	// can't force unbounded buffering.
+	r.Body = http.MaxBytesReader(w, r.Body, config.WebhookMaxBodyBytes)
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
 		return
 	}
 
@@ -175,13 +378,27 @@ func handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Reject deliveries for a different GitHub App than this server is
+	// configured for.
+	if err := webhook.CheckInstallationTarget(r.Header.Get("X-GitHub-Hook-Installation-Target-ID"), config.GitHubAppID); err != nil {
+		http.Error(w, "Installation target mismatch", http.StatusForbidden)
+		return
+	}
+
+	// Reject replays of a delivery we've already accepted.
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if deliveryID != "" && deliveryCache.Seen(deliveryID, time.Now()) {
+		http.Error(w, "Duplicate delivery", http.StatusConflict)
+		return
+	}
+
 	// Check event type
 	eventType := r.Header.Get("X-GitHub-Event")
-	log.Printf("Received GitHub event: %s", eventType)
+	log.Printf("Received GitHub event: %s (delivery: %s)", eventType, deliveryID)
 
 	switch eventType {
 	case "pull_request":
-		handlePREvent(w, body)
+		handlePREvent(w, r, body)
 	case "ping":
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]string{"message": "pong"})
@@ -191,7 +408,12 @@ func handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handlePREvent(w http.ResponseWriter, body []byte) {
+// handlePREvent only enqueues the event and returns 202; the worker pool
+// started in main owns actually running processPR. Persisting the job
+// keyed by X-GitHub-Delivery before returning means a crash after this
+// point loses nothing -- the job is durable and GitHub's retry of an
+// un-acked delivery lands on the same row instead of a duplicate.
+func handlePREvent(w http.ResponseWriter, r *http.Request, body []byte) {
 	var event PREvent
 	if err := json.Unmarshal(body, &event); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -205,22 +427,78 @@ func handlePREvent(w http.ResponseWriter, body []byte) {
 		return
 	}
 
-	log.Printf("Processing PR #%d on %s (action: %s)",
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if deliveryID == "" {
+		http.Error(w, "Missing X-GitHub-Delivery header", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Enqueueing PR #%d on %s (action: %s, delivery: %s)",
 		event.PullRequest.Number,
 		event.Repository.FullName,
-		event.Action)
+		event.Action,
+		deliveryID)
 
-	// Process asynchronously
-	go processPR(event)
+	job, err := jobStore.Enqueue(r.Context(), deliveryID, body)
+	if err != nil {
+		log.Printf("Error enqueueing delivery %s: %v", deliveryID, err)
+		http.Error(w, "Failed to enqueue job", http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "processing",
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "queued",
+		"job_id":  job.ID,
 		"pr":      fmt.Sprintf("%d", event.PullRequest.Number),
 	})
 }
 
-func processPR(event PREvent) {
+// processQueuedJob is the queue.Handler the worker pool runs for each
+// claimed job: it unmarshals the stored webhook payload and hands off to
+// processPR, bounding the analysis to AnalysisTimeout.
+func processQueuedJob(ctx context.Context, job *queue.Job) error {
+	var event PREvent
+	if err := json.Unmarshal(job.Payload, &event); err != nil {
+		return fmt.Errorf("unmarshaling job %d payload: %w", job.ID, err)
+	}
+
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.AnalysisTimeout)
+	defer cancel()
+	return processPR(jobCtx, event)
+}
+
+// handleJobStatus serves GET /api/v1/jobs/{id}, reporting a queued job's
+// current status for callers polling after a 202.
+func handleJobStatus(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/v1/jobs/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := jobStore.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":              job.ID,
+		"delivery_id":     job.DeliveryID,
+		"status":          job.Status,
+		"attempts":        job.Attempts,
+		"max_attempts":    job.MaxAttempts,
+		"last_error":      job.LastError,
+		"next_attempt_at": job.NextAttemptAt,
+		"created_at":      job.CreatedAt,
+		"updated_at":      job.UpdatedAt,
+	})
+}
+
+func processPR(ctx context.Context, event PREvent) error {
 	repo := event.Repository.FullName
 	prNumber := event.PullRequest.Number
 	sha := event.PullRequest.Head.SHA
@@ -229,7 +507,7 @@ func processPR(event PREvent) {
 	log.Printf("Starting analysis for %s#%d (sha: %s)", repo, prNumber, sha[:8])
 
 	// Create pending check run
-	createCheckRun(repo, sha, installationID, CheckRun{
+	checkRunID, err := createCheckRun(ctx, repo, sha, installationID, CheckRun{
 		Name:      "VibeGuard",
 		HeadSHA:   sha,
 		Status:    "in_progress",
@@ -239,21 +517,24 @@ func processPR(event PREvent) {
 			Summary: "VibeGuard is scanning this PR for AI-generated code.",
 		},
 	})
+	if err != nil {
+		log.Printf("Error creating check run: %v", err)
+	}
 
 	// Get PR diff/files
-	files, err := getPRFiles(repo, prNumber, installationID)
+	files, err := getPRFiles(ctx, repo, prNumber, installationID, sha)
 	if err != nil {
 		log.Printf("Error getting PR files: %v", err)
-		completeCheckRun(repo, sha, installationID, "failure", "Error", "Failed to fetch PR files")
-		return
+		completeCheckRun(ctx, repo, sha, installationID, checkRunID, "failure", "Error", "Failed to fetch PR files", nil)
+		return fmt.Errorf("getting PR files for %s#%d: %w", repo, prNumber, err)
 	}
 
 	// Analyze files
-	result, err := analyzeFiles(files)
+	result, err := analyzer.Analyze(ctx, files)
 	if err != nil {
 		log.Printf("Error analyzing files: %v", err)
-		completeCheckRun(repo, sha, installationID, "failure", "Error", "Analysis failed")
-		return
+		completeCheckRun(ctx, repo, sha, installationID, checkRunID, "failure", "Error", "Analysis failed", nil)
+		return fmt.Errorf("analyzing %s#%d: %w", repo, prNumber, err)
 	}
 
 	// Determine conclusion
@@ -269,55 +550,9 @@ func processPR(event PREvent) {
 		title = fmt.Sprintf("âš  Warning â€” %d%% AI-generated", int(result.AIPercentage))
 	}
 
-	completeCheckRun(repo, sha, installationID, conclusion, title, summary)
+	completeCheckRun(ctx, repo, sha, installationID, checkRunID, conclusion, title, summary, buildAnnotations(result.Results))
 	log.Printf("Completed analysis for %s#%d: %s", repo, prNumber, conclusion)
-}
-
-func analyzeFiles(files []PRFile) (*AnalysisResult, error) {
-	// Build request for Python analyzer
-	var fileData []map[string]string
-	for _, f := range files {
-		if f.Content != "" {
-			fileData = append(fileData, map[string]string{
-				"path":    f.Path,
-				"content": f.Content,
-			})
-		}
-	}
-
-	reqBody, _ := json.Marshal(map[string]interface{}{
-		"files": fileData,
-	})
-
-	// Call Python API (or inline Python)
-	cmd := exec.Command(config.PythonPath, "-c", fmt.Sprintf(`
-import sys
-sys.path.insert(0, '%s')
-from api.server import app
-import json
-
-with app.test_client() as client:
-    resp = client.post('/api/v1/scan', 
-        data='%s',
-        content_type='application/json')
-    print(resp.data.decode())
-`, getProjectRoot(), string(reqBody)))
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback: return mock result
-		return &AnalysisResult{
-			FilesScanned:    len(files),
-			AIDetected:      len(files) / 2,
-			MaxAIConfidence: 0.75,
-			AIPercentage:    45.0,
-			Blocked:         false,
-		}, nil
-	}
-
-	var result AnalysisResult
-	json.Unmarshal(output, &result)
-	return &result, nil
+	return nil
 }
 
 func generateSummary(result *AnalysisResult) string {
@@ -366,29 +601,158 @@ func generateSummary(result *AnalysisResult) string {
 
 // PRFile represents a file in a PR
 type PRFile struct {
-	Path    string
-	Content string
-	Status  string
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Status  string `json:"status"`
 }
 
-func getPRFiles(repo string, prNumber, installationID int) ([]PRFile, error) {
-	// TODO: Use GitHub API with installation token
-	// For now, return empty (will be filled when GitHub App is configured)
-	return []PRFile{}, nil
+// splitRepoFullName splits a GitHub "owner/repo" full name into its parts,
+// as the go-github client's per-repo calls take them separately.
+func splitRepoFullName(fullName string) (owner, repo string, err error) {
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repo full name %q", fullName)
+	}
+	return parts[0], parts[1], nil
 }
 
-func createCheckRun(repo, sha string, installationID int, check CheckRun) error {
-	// TODO: Implement GitHub Check Runs API
-	log.Printf("Would create check run for %s @ %s: %s", repo, sha[:8], check.Status)
-	return nil
+// getPRFiles fetches the PR's changed files via the GitHub API and, for
+// every file that wasn't removed, its content at sha so the Analyzer has
+// something to score.
+func getPRFiles(ctx context.Context, repo string, prNumber, installationID int, sha string) ([]PRFile, error) {
+	if ghApp == nil {
+		log.Printf("No GitHub App configured, skipping PR file fetch for %s#%d", repo, prNumber)
+		return nil, nil
+	}
+
+	owner, name, err := splitRepoFullName(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	client := ghApp.InstallationClient(int64(installationID))
+	ghFiles, err := client.GetPRFiles(ctx, owner, name, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("fetching PR files: %w", err)
+	}
+
+	files := make([]PRFile, 0, len(ghFiles))
+	for _, f := range ghFiles {
+		if f.Status == "removed" {
+			files = append(files, PRFile{Path: f.Filename, Status: f.Status})
+			continue
+		}
+
+		content, err := client.GetFileContent(ctx, owner, name, f.Filename, sha)
+		if err != nil {
+			log.Printf("Error fetching content for %s @ %s: %v", f.Filename, sha[:8], err)
+			continue
+		}
+		files = append(files, PRFile{Path: f.Filename, Content: content, Status: f.Status})
+	}
+	return files, nil
+}
+
+// createCheckRun creates the pending "VibeGuard" check run and returns its
+// ID so completeCheckRun can update the same run rather than creating a
+// second one.
+func createCheckRun(ctx context.Context, repo, sha string, installationID int, check CheckRun) (int64, error) {
+	if ghApp == nil {
+		log.Printf("No GitHub App configured, would create check run for %s @ %s: %s", repo, sha[:8], check.Status)
+		return 0, nil
+	}
+
+	owner, name, err := splitRepoFullName(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := ghApp.InstallationClient(int64(installationID)).CreateCheckRun(ctx, owner, name, toGitHubCheckRun(check))
+	if err != nil {
+		return 0, fmt.Errorf("creating check run: %w", err)
+	}
+	return resp.ID, nil
 }
 
-func completeCheckRun(repo, sha string, installationID int, conclusion, title, summary string) error {
-	// TODO: Implement GitHub Check Runs API
-	log.Printf("Would complete check run for %s @ %s: %s - %s", repo, sha[:8], conclusion, title)
+// completeCheckRun finalizes the check run, splitting annotations across
+// multiple update calls when there are more than maxAnnotationsPerCheckRun:
+// only the last batch carries the conclusion, matching the Checks API's
+// requirement that a run stay "in_progress" until its final update.
+func completeCheckRun(ctx context.Context, repo, sha string, installationID int, checkRunID int64, conclusion, title, summary string, annotations []Annotation) error {
+	batches := batchAnnotations(annotations, maxAnnotationsPerCheckRun)
+	if len(batches) == 0 {
+		batches = [][]Annotation{nil}
+	}
+
+	if ghApp == nil {
+		for i, batch := range batches {
+			if i < len(batches)-1 {
+				log.Printf("No GitHub App configured, would update check run for %s @ %s with %d annotations (batch %d/%d)", repo, sha[:8], len(batch), i+1, len(batches))
+				continue
+			}
+			log.Printf("No GitHub App configured, would complete check run for %s @ %s: %s - %s (%d annotations, batch %d/%d)", repo, sha[:8], conclusion, title, len(batch), i+1, len(batches))
+		}
+		return nil
+	}
+
+	owner, name, err := splitRepoFullName(repo)
+	if err != nil {
+		return err
+	}
+	client := ghApp.InstallationClient(int64(installationID))
+
+	for i, batch := range batches {
+		check := CheckRun{Output: &CheckOutput{Annotations: batch}}
+		if i < len(batches)-1 {
+			check.Status = "in_progress"
+			check.Output.Title = title
+			check.Output.Summary = summary
+		} else {
+			check.Status = "completed"
+			check.Conclusion = conclusion
+			check.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+			check.Output.Title = title
+			check.Output.Summary = summary
+		}
+		if err := client.UpdateCheckRun(ctx, owner, name, checkRunID, toGitHubCheckRun(check)); err != nil {
+			return fmt.Errorf("updating check run (batch %d/%d): %w", i+1, len(batches), err)
+		}
+	}
 	return nil
 }
 
+// toGitHubCheckRun converts cmd/server's CheckRun (the shape handlers and
+// generateSummary build) into internal/github's identical type, which is
+// what Client's Checks API methods take.
+func toGitHubCheckRun(check CheckRun) github.CheckRun {
+	out := github.CheckRun{
+		Name:        check.Name,
+		HeadSHA:     check.HeadSHA,
+		Status:      check.Status,
+		Conclusion:  check.Conclusion,
+		StartedAt:   check.StartedAt,
+		CompletedAt: check.CompletedAt,
+	}
+	if check.Output != nil {
+		out.Output = &github.CheckOutput{
+			Title:   check.Output.Title,
+			Summary: check.Output.Summary,
+			Text:    check.Output.Text,
+		}
+		for _, a := range check.Output.Annotations {
+			out.Output.Annotations = append(out.Output.Annotations, github.Annotation{
+				Path:            a.Path,
+				StartLine:       a.StartLine,
+				EndLine:         a.EndLine,
+				AnnotationLevel: a.AnnotationLevel,
+				Message:         a.Message,
+				Title:           a.Title,
+			})
+		}
+	}
+	return out
+}
+
 func verifySignature(payload []byte, signature, secret string) bool {
 	if !strings.HasPrefix(signature, "sha256=") {
 		return false
@@ -407,35 +771,41 @@ func getProjectRoot() string {
 	return "/root/clawd/vibeguard"
 }
 
+// analyzeRequest is the body for /api/v1/analyze: a single file's code.
+type analyzeRequest struct {
+	Code     string `json:"code"`
+	Filename string `json:"filename"`
+}
+
+// handleAnalyze scores a single snippet of code, bypassing the Analyzer/
+// policy pipeline since there's no PR or policy config to evaluate it
+// against -- just the raw detection.AnalyzeCode result.
 func handleAnalyze(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	body, _ := io.ReadAll(r.Body)
-	
-	// Forward to Python API
-	cmd := exec.Command(config.PythonPath, "-c", fmt.Sprintf(`
-import sys
-sys.path.insert(0, '/root/clawd/vibeguard')
-from detection.stylometry import analyze_code
-import json
-
-data = json.loads('%s')
-code = data.get('code', '')
-result = analyze_code(code)
-print(json.dumps(result))
-`, strings.ReplaceAll(string(body), "'", "\\'")))
+	var req analyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
 
-	output, err := cmd.Output()
+	score, err := detection.AnalyzeCode(req.Code, detection.LanguageFromExtension(filepath.Ext(req.Filename)))
 	if err != nil {
 		http.Error(w, "Analysis failed", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(output)
+	json.NewEncoder(w).Encode(score)
+}
+
+// scanRequest is the body for /api/v1/scan: the files to run through the
+// configured Analyzer, same shape processPR builds from a PR's diff.
+type scanRequest struct {
+	Files []PRFile `json:"files"`
 }
 
 func handleScan(w http.ResponseWriter, r *http.Request) {
@@ -444,36 +814,18 @@ func handleScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	body, _ := io.ReadAll(r.Body)
-	
-	// Forward to Python API
-	cmd := exec.Command(config.PythonPath, "-c", fmt.Sprintf(`
-import sys
-sys.path.insert(0, '/root/clawd/vibeguard')
-from api.server import app
-import json
-
-with app.test_client() as client:
-    resp = client.post('/api/v1/scan', 
-        data='''%s''',
-        content_type='application/json')
-    print(resp.data.decode())
-`, strings.ReplaceAll(string(body), "'", "\\'")))
+	var req scanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
 
-	output, err := cmd.Output()
+	result, err := analyzer.Analyze(r.Context(), req.Files)
 	if err != nil {
-		// Return mock response
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":           "completed",
-			"files_scanned":    0,
-			"ai_detected":      0,
-			"max_ai_confidence": 0,
-			"blocked":          false,
-		})
+		http.Error(w, "Analysis failed", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(output)
+	json.NewEncoder(w).Encode(result)
 }
@@ -0,0 +1,94 @@
+// Package baseline records per-file AI-confidence scores from a scan so
+// later scans can flag only files whose AI content materially increased,
+// instead of every file above the static threshold. This lets a team adopt
+// VibeGuard on an existing AI-heavy codebase without a day-one flood of
+// violations: the baseline captures where things stand today, and policy
+// only fires on drift from there.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rohansx/vibeguard.io/internal/detection"
+)
+
+// SchemaVersion is bumped whenever the on-disk baseline.json shape changes
+// incompatibly. Load refuses to read a baseline written by an older or
+// newer schema rather than silently misinterpreting it.
+const SchemaVersion = 1
+
+// DefaultPath is where `baseline create` writes and `scan --baseline` reads
+// by convention, committed alongside the rest of the repo.
+const DefaultPath = ".vibeguard/baseline.json"
+
+// DefaultDelta is the minimum increase in AI confidence (0-1) a file must
+// show over its baseline before --baseline reports it.
+const DefaultDelta = 0.15
+
+// Baseline is the parsed form of .vibeguard/baseline.json: one AI-confidence
+// score per file path, plus the schema and detector versions it was
+// recorded with so stale or incompatible baselines are caught instead of
+// silently producing meaningless deltas.
+type Baseline struct {
+	SchemaVersion   int                `json:"schema_version"`
+	DetectorVersion string             `json:"detector_version"`
+	Files           map[string]float64 `json:"files"`
+}
+
+// New creates an empty baseline stamped with the current schema and
+// detector versions, ready for Set calls.
+func New() *Baseline {
+	return &Baseline{
+		SchemaVersion:   SchemaVersion,
+		DetectorVersion: detection.Version,
+		Files:           map[string]float64{},
+	}
+}
+
+// Set records path's current AI confidence.
+func (b *Baseline) Set(path string, confidence float64) {
+	b.Files[path] = confidence
+}
+
+// Load parses a baseline.json and refuses to return one recorded by an
+// incompatible schema or detector version, since deltas against those would
+// not mean what the caller expects.
+func Load(data []byte) (*Baseline, error) {
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("baseline: parse: %w", err)
+	}
+	if b.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("baseline: schema version %d is incompatible with %d", b.SchemaVersion, SchemaVersion)
+	}
+	if b.DetectorVersion != detection.Version {
+		return nil, fmt.Errorf("baseline: recorded with detector version %q, current detector is %q; run `vibeguard baseline create` again", b.DetectorVersion, detection.Version)
+	}
+	if b.Files == nil {
+		b.Files = map[string]float64{}
+	}
+	return &b, nil
+}
+
+// Save serializes b as indented JSON suitable for committing to the repo.
+func (b *Baseline) Save() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("baseline: marshal: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Delta reports how much confidence has increased for path since the
+// baseline was recorded, and whether path is new (absent from the
+// baseline). A new file's delta is its full current confidence, so it
+// drifts past a delta threshold the same way a file that went from 0 to
+// that confidence would.
+func (b *Baseline) Delta(path string, confidence float64) (delta float64, isNew bool) {
+	prev, ok := b.Files[path]
+	if !ok {
+		return confidence, true
+	}
+	return confidence - prev, false
+}
@@ -0,0 +1,437 @@
+// Package detection implements the VibeGuard stylometry engine in pure Go.
+//
+// It replaces the previous python3 subprocess backend (see stylometry.py in
+// the legacy vibeguard prototype): the same lexical/stylometric signals are
+// computed natively so the CLI and server binaries are self-contained and do
+// not depend on a Python interpreter or a hard-coded filesystem layout.
+package detection
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Version identifies the detector's scoring behavior. It is bumped whenever
+// a change to the feature weights or thresholds would shift AIScore values
+// for the same input, so consumers that persist scores (e.g. internal/
+// baseline) can detect when a stored score is no longer comparable to a
+// freshly computed one.
+const Version = "1"
+
+// Features holds the raw stylometric measurements extracted from a source
+// file. They are exported so calibration tooling and tests can inspect and
+// tune individual signals independently of the combined AIScore.
+type Features struct {
+	CommentDensity      float64 // fraction of non-blank lines that are comments
+	IdentifierEntropy   float64 // shannon entropy (bits) of identifier characters
+	AvgLineLength       float64 // mean characters per non-blank line
+	BlankLineRatio      float64 // fraction of lines that are blank
+	IdiomRepetition     float64 // frequency of common AI-generated idioms
+	NGramDivergence     float64 // fraction of token 3-grams that repeat within the file; not compared against a baseline corpus
+	AvgIdentifierLength float64 // mean characters per identifier
+	ComplexityVariance  float64 // variance of per-function cyclomatic complexity
+}
+
+// AIScore is the result of analyzing a single file: a combined probability
+// that the content is AI-generated, plus the features that produced it.
+type AIScore struct {
+	Probability float64  `json:"ai_probability"`
+	Features    Features `json:"features"`
+}
+
+// commonAIIdioms are phrases/snippets that appear disproportionately often
+// in AI-generated code across languages: boilerplate comments, hedging
+// language, and defensive scaffolding that a human author rarely bothers
+// to write by hand.
+var commonAIIdioms = []string{
+	"todo: implement",
+	"this function",
+	"note:",
+	"important:",
+	"for example",
+	"in this case",
+	"make sure to",
+	"it's worth noting",
+	"let's",
+	"first, we",
+	"here we",
+	"// this is",
+	"# this is",
+}
+
+var identifierRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// functionStartRe marks the start of a function-like declaration. It only
+// recognizes languages with a dedicated function keyword (Go, Python, Rust,
+// JS/TS); brace-language declarations without one (Java, C, C#) aren't
+// split out, so complexityVariance degrades to 0 for those files rather
+// than guessing at a declaration boundary.
+var functionStartRe = regexp.MustCompile(`(?m)^\s*(?:func|def|fn|function)\s`)
+
+// decisionKeywordRe matches the branching keywords counted toward
+// cyclomatic complexity: each one is an additional path through the
+// function, on top of the implicit one for entering it at all.
+var decisionKeywordRe = regexp.MustCompile(`\b(?:if|elif|for|while|case|catch|except)\b`)
+
+var commentPrefixByLanguage = map[string][]string{
+	"python":     {"#"},
+	"ruby":       {"#"},
+	"go":         {"//"},
+	"javascript": {"//"},
+	"typescript": {"//"},
+	"java":       {"//"},
+	"kotlin":     {"//"},
+	"rust":       {"//"},
+	"c":          {"//"},
+	"cpp":        {"//"},
+	"csharp":     {"//"},
+	"swift":      {"//"},
+	"php":        {"//", "#"},
+}
+
+// defaultCommentPrefixes is used for languages we don't special-case above;
+// "//" covers the majority of the extensions findFiles looks for.
+var defaultCommentPrefixes = []string{"//", "#"}
+
+// AnalyzeCode computes stylometric features for content and combines them
+// into a single AI-generation probability. language is a best-effort hint
+// (e.g. "go", "python") derived from the file extension; an unrecognized or
+// empty value falls back to defaultCommentPrefixes.
+func AnalyzeCode(content, language string) (AIScore, error) {
+	if strings.TrimSpace(content) == "" {
+		return AIScore{}, fmt.Errorf("detection: empty content")
+	}
+
+	lines := strings.Split(content, "\n")
+	features := Features{
+		CommentDensity:      commentDensity(lines, language),
+		IdentifierEntropy:   identifierEntropy(content),
+		AvgLineLength:       avgLineLength(lines),
+		BlankLineRatio:      blankLineRatio(lines),
+		IdiomRepetition:     idiomRepetition(content),
+		NGramDivergence:     ngramDivergence(content),
+		AvgIdentifierLength: avgIdentifierLength(content),
+		ComplexityVariance:  complexityVariance(content),
+	}
+
+	return AIScore{
+		Probability: combine(features),
+		Features:    features,
+	}, nil
+}
+
+// DefaultWindowLines is the window size AnalyzeWindows uses when callers
+// don't need a different granularity.
+const DefaultWindowLines = 40
+
+// LineRange is one window's AI-generation score, used to drive inline
+// annotations (e.g. GitHub Check Run annotations) rather than a single
+// whole-file verdict.
+type LineRange struct {
+	StartLine   int     `json:"start_line"`
+	EndLine     int     `json:"end_line"`
+	Probability float64 `json:"ai_probability"`
+}
+
+// AnalyzeWindows scores content in consecutive, non-overlapping chunks of
+// windowLines lines (falling back to DefaultWindowLines when <= 0), each run
+// through AnalyzeCode independently. This trades the cross-window context
+// AnalyzeCode's whole-file features could use for the ability to localize a
+// high-confidence region to specific lines. Blank-only chunks are skipped.
+func AnalyzeWindows(content, language string, windowLines int) ([]LineRange, error) {
+	if windowLines <= 0 {
+		windowLines = DefaultWindowLines
+	}
+
+	lines := strings.Split(content, "\n")
+	var ranges []LineRange
+	for start := 0; start < len(lines); start += windowLines {
+		end := start + windowLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		chunk := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+
+		score, err := AnalyzeCode(chunk, language)
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, LineRange{
+			StartLine:   start + 1,
+			EndLine:     end,
+			Probability: score.Probability,
+		})
+	}
+	return ranges, nil
+}
+
+func commentPrefixes(language string) []string {
+	if prefixes, ok := commentPrefixByLanguage[strings.ToLower(language)]; ok {
+		return prefixes
+	}
+	return defaultCommentPrefixes
+}
+
+func commentDensity(lines []string, language string) float64 {
+	prefixes := commentPrefixes(language)
+	var nonBlank, comments int
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		nonBlank++
+		for _, p := range prefixes {
+			if strings.HasPrefix(trimmed, p) {
+				comments++
+				break
+			}
+		}
+	}
+	if nonBlank == 0 {
+		return 0
+	}
+	return float64(comments) / float64(nonBlank)
+}
+
+// identifierEntropy computes the Shannon entropy, in bits, of the character
+// distribution across all identifiers in content. AI-generated code tends
+// toward longer, more uniformly-descriptive identifiers, which pulls entropy
+// toward a narrower, more predictable band than hand-written code.
+func identifierEntropy(content string) float64 {
+	idents := identifierRe.FindAllString(content, -1)
+	if len(idents) == 0 {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	var total int
+	for _, ident := range idents {
+		for _, r := range ident {
+			counts[unicode.ToLower(r)]++
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func avgLineLength(lines []string) float64 {
+	var nonBlank, totalLen int
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		nonBlank++
+		totalLen += len(trimmed)
+	}
+	if nonBlank == 0 {
+		return 0
+	}
+	return float64(totalLen) / float64(nonBlank)
+}
+
+func blankLineRatio(lines []string) float64 {
+	if len(lines) == 0 {
+		return 0
+	}
+	var blank int
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank++
+		}
+	}
+	return float64(blank) / float64(len(lines))
+}
+
+func idiomRepetition(content string) float64 {
+	lower := strings.ToLower(content)
+	var hits int
+	for _, idiom := range commonAIIdioms {
+		hits += strings.Count(lower, idiom)
+	}
+	lineCount := strings.Count(content, "\n") + 1
+	if lineCount == 0 {
+		return 0
+	}
+	// Normalize to hits per 50 lines so the signal doesn't just track file size.
+	return float64(hits) / (float64(lineCount) / 50.0)
+}
+
+// avgIdentifierLength is the mean character length of identifiers in
+// content. AI-generated code tends toward longer, more self-documenting
+// names than hand-written code, which often leans on short, familiar ones.
+func avgIdentifierLength(content string) float64 {
+	idents := identifierRe.FindAllString(content, -1)
+	if len(idents) == 0 {
+		return 0
+	}
+	var total int
+	for _, ident := range idents {
+		total += len(ident)
+	}
+	return float64(total) / float64(len(idents))
+}
+
+// complexityVariance splits content into function bodies (see
+// functionStartRe) and returns the population variance of their cyclomatic
+// complexity. AI-generated code tends to produce functions of uniformly
+// modest complexity, so low variance across a file's functions is itself a
+// signal; files with fewer than two recognized functions yield 0.
+func complexityVariance(content string) float64 {
+	bodies := functionBodies(content)
+	if len(bodies) < 2 {
+		return 0
+	}
+
+	complexities := make([]float64, len(bodies))
+	var sum float64
+	for i, body := range bodies {
+		c := float64(cyclomaticComplexity(body))
+		complexities[i] = c
+		sum += c
+	}
+	mean := sum / float64(len(complexities))
+
+	var variance float64
+	for _, c := range complexities {
+		d := c - mean
+		variance += d * d
+	}
+	return variance / float64(len(complexities))
+}
+
+// functionBodies splits content at each functionStartRe match, returning
+// the text from each match up to (but not including) the next one.
+func functionBodies(content string) []string {
+	starts := functionStartRe.FindAllStringIndex(content, -1)
+	if len(starts) < 2 {
+		return nil
+	}
+
+	bodies := make([]string, 0, len(starts))
+	for i, s := range starts {
+		end := len(content)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		bodies = append(bodies, content[s[0]:end])
+	}
+	return bodies
+}
+
+// cyclomaticComplexity is 1 (the function's single entry path) plus one for
+// every decision keyword found in body.
+func cyclomaticComplexity(body string) int {
+	return 1 + len(decisionKeywordRe.FindAllString(body, -1))
+}
+
+// ngramDivergence hashes overlapping 3-token windows and measures how
+// concentrated the distribution is (via normalized repeat rate). AI-generated
+// code tends to reuse the same small set of structural n-grams (e.g.
+// "if err != nil", "for i in range") far more than organically written code,
+// so higher divergence from a flat distribution indicates higher AI-likelihood.
+func ngramDivergence(content string) float64 {
+	tokens := identifierRe.FindAllString(content, -1)
+	if len(tokens) < 3 {
+		return 0
+	}
+
+	counts := make(map[string]int)
+	var total int
+	for i := 0; i+2 < len(tokens); i++ {
+		gram := tokens[i] + " " + tokens[i+1] + " " + tokens[i+2]
+		counts[gram]++
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var repeated int
+	for _, c := range counts {
+		if c > 1 {
+			repeated += c
+		}
+	}
+	return float64(repeated) / float64(total)
+}
+
+// combine folds the individual features into a single [0, 1] probability.
+// Weights were hand-tuned against the same calibration set used by the
+// legacy Python detector. There's no tooling in this repo to re-tune them
+// yet; retuning today means editing the literals below directly.
+func combine(f Features) float64 {
+	score := 0.0
+	score += clamp01(f.CommentDensity) * 0.135
+	score += clamp01(1-math.Abs(f.IdentifierEntropy-3.8)/3.8) * 0.18
+	score += clamp01(f.AvgLineLength/120.0) * 0.09
+	score += clamp01(1-math.Abs(f.BlankLineRatio-0.18)/0.18) * 0.135
+	score += clamp01(f.IdiomRepetition/3.0) * 0.18
+	score += clamp01(f.NGramDivergence) * 0.18
+	score += clamp01(f.AvgIdentifierLength/14.0) * 0.05
+	score += clamp01(1-f.ComplexityVariance/6.0) * 0.05
+	return clamp01(score)
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// LanguageFromExtension maps a file extension (including the leading dot,
+// e.g. ".go") to the language hint AnalyzeCode expects. Unknown extensions
+// return an empty string, which falls back to generic comment handling.
+func LanguageFromExtension(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".py":
+		return "python"
+	case ".rb":
+		return "ruby"
+	case ".go":
+		return "go"
+	case ".js", ".jsx":
+		return "javascript"
+	case ".ts", ".tsx":
+		return "typescript"
+	case ".java":
+		return "java"
+	case ".kt":
+		return "kotlin"
+	case ".rs":
+		return "rust"
+	case ".c", ".h":
+		return "c"
+	case ".cpp":
+		return "cpp"
+	case ".cs":
+		return "csharp"
+	case ".swift":
+		return "swift"
+	case ".php":
+		return "php"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,79 @@
+package detection
+
+import "testing"
+
+func TestAnalyzeCodeEmptyContent(t *testing.T) {
+	if _, err := AnalyzeCode("   \n\n", "go"); err == nil {
+		t.Fatal("expected an error for empty/blank content")
+	}
+}
+
+func TestAnalyzeCodeProbabilityInRange(t *testing.T) {
+	score, err := AnalyzeCode("func add(a, b int) int {\n\treturn a + b\n}\n", "go")
+	if err != nil {
+		t.Fatalf("AnalyzeCode: %v", err)
+	}
+	if score.Probability < 0 || score.Probability > 1 {
+		t.Fatalf("probability %v out of [0, 1] range", score.Probability)
+	}
+}
+
+func TestAvgIdentifierLength(t *testing.T) {
+	got := avgIdentifierLength("a bb ccc")
+	want := (1.0 + 2.0 + 3.0) / 3.0
+	if got != want {
+		t.Fatalf("avgIdentifierLength = %v, want %v", got, want)
+	}
+
+	if got := avgIdentifierLength("   "); got != 0 {
+		t.Fatalf("avgIdentifierLength of no identifiers = %v, want 0", got)
+	}
+}
+
+func TestComplexityVarianceNeedsAtLeastTwoFunctions(t *testing.T) {
+	single := "func one() {\n\tif true {\n\t}\n}\n"
+	if got := complexityVariance(single); got != 0 {
+		t.Fatalf("complexityVariance with one function = %v, want 0", got)
+	}
+}
+
+func TestComplexityVarianceAcrossFunctions(t *testing.T) {
+	src := `func simple() {
+	return
+}
+
+func branchy() {
+	if a {
+	}
+	for b {
+	}
+	if c {
+	}
+}
+`
+	got := complexityVariance(src)
+	if got <= 0 {
+		t.Fatalf("expected positive variance between a complexity-1 and a complexity-4 function, got %v", got)
+	}
+}
+
+func TestCyclomaticComplexity(t *testing.T) {
+	body := "func f() {\n\tif a {\n\t}\n\tfor c {\n\t}\n}\n"
+	if got := cyclomaticComplexity(body); got != 3 {
+		t.Fatalf("cyclomaticComplexity = %d, want 3 (1 base + if + for)", got)
+	}
+}
+
+func TestLanguageFromExtension(t *testing.T) {
+	cases := map[string]string{
+		".go":  "go",
+		".py":  "python",
+		".rs":  "rust",
+		".xyz": "",
+	}
+	for ext, want := range cases {
+		if got := LanguageFromExtension(ext); got != want {
+			t.Errorf("LanguageFromExtension(%q) = %q, want %q", ext, got, want)
+		}
+	}
+}
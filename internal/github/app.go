@@ -1,224 +1,290 @@
+// Package github wraps the GitHub Checks/Pulls/Contents APIs VibeGuard
+// needs to report scan results on a PR. It is a thin adapter over
+// google/go-github and bradleyfalzon/ghinstallation, rather than a
+// hand-rolled REST client, so auth and pagination come from those
+// libraries instead of being reimplemented here. go-github surfaces rate
+// limit responses as typed errors but doesn't retry them itself, so
+// Client's methods do that part: see withRateLimitRetry.
 package github
 
 import (
-	"bytes"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/json"
-	"encoding/pem"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	gh "github.com/google/go-github/v55/github"
 )
 
-// App represents a GitHub App
+// App represents a GitHub App. It mints Clients scoped to one installation
+// at a time; ghinstallation handles minting and auto-refreshing each
+// installation's access token, so App itself holds no token state.
 type App struct {
-	ID         string
-	PrivateKey *rsa.PrivateKey
-	httpClient *http.Client
+	transport *ghinstallation.AppsTransport
 }
 
-// Installation represents a GitHub App installation
-type Installation struct {
-	ID          int    `json:"id"`
-	Account     Account `json:"account"`
-	AccessToken string  `json:"-"`
+// NewApp creates a GitHub App client from the app's numeric ID and PEM-
+// encoded private key.
+func NewApp(appID, privateKeyPEM string) (*App, error) {
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("github: invalid app id %q: %w", appID, err)
+	}
+
+	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, id, []byte(privateKeyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("github: building app transport: %w", err)
+	}
+
+	return &App{transport: atr}, nil
 }
 
-type Account struct {
-	Login string `json:"login"`
-	Type  string `json:"type"`
+// InstallationClient returns a Client authenticated as the given
+// installation. The returned client's token is minted lazily on first use
+// and refreshed automatically as it nears expiry.
+func (a *App) InstallationClient(installationID int64) *Client {
+	itr := ghinstallation.NewFromAppsTransport(a.transport, installationID)
+	return &Client{gh: gh.NewClient(&http.Client{Transport: itr, Timeout: 30 * time.Second})}
 }
 
-// AccessToken represents an installation access token
-type AccessToken struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
+// Client represents an authenticated GitHub client.
+type Client struct {
+	gh *gh.Client
 }
 
-// NewApp creates a new GitHub App client
-func NewApp(appID, privateKeyPEM string) (*App, error) {
-	block, _ := pem.Decode([]byte(privateKeyPEM))
-	if block == nil {
-		return nil, fmt.Errorf("failed to parse PEM block")
+// NewClient creates a Client authenticated with a plain token (e.g. a
+// personal access token, or an installation token minted elsewhere), for
+// callers that don't need ghinstallation's auto-refresh.
+func NewClient(token string) *Client {
+	return &Client{gh: gh.NewClient(nil).WithAuthToken(token)}
+}
+
+// CreateCheckRun creates a new check run.
+func (c *Client) CreateCheckRun(ctx context.Context, owner, repo string, check CheckRun) (*CheckRunResponse, error) {
+	var run *gh.CheckRun
+	err := withRateLimitRetry(ctx, func() error {
+		var err error
+		run, _, err = c.gh.Checks.CreateCheckRun(ctx, owner, repo, toCreateOpts(check))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("github: creating check run: %w", err)
 	}
+	return &CheckRunResponse{ID: run.GetID()}, nil
+}
 
-	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+// UpdateCheckRun updates an existing check run.
+func (c *Client) UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, check CheckRun) error {
+	err := withRateLimitRetry(ctx, func() error {
+		_, _, err := c.gh.Checks.UpdateCheckRun(ctx, owner, repo, checkRunID, toUpdateOpts(check))
+		return err
+	})
 	if err != nil {
-		// Try PKCS8
-		keyInterface, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		return fmt.Errorf("github: updating check run: %w", err)
+	}
+	return nil
+}
+
+// GetPRFiles gets the files changed in a pull request, following pagination
+// so PRs touching more than one page of files (30 by default) aren't
+// silently truncated.
+func (c *Client) GetPRFiles(ctx context.Context, owner, repo string, prNumber int) ([]PRFile, error) {
+	var files []PRFile
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		var ghFiles []*gh.CommitFile
+		var resp *gh.Response
+		err := withRateLimitRetry(ctx, func() error {
+			var err error
+			ghFiles, resp, err = c.gh.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse private key: %v", err)
-		}
-		var ok bool
-		key, ok = keyInterface.(*rsa.PrivateKey)
-		if !ok {
-			return nil, fmt.Errorf("not an RSA key")
+			return nil, fmt.Errorf("github: listing PR files: %w", err)
 		}
-	}
 
-	return &App{
-		ID:         appID,
-		PrivateKey: key,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-	}, nil
-}
+		for _, f := range ghFiles {
+			files = append(files, PRFile{
+				SHA:       f.GetSHA(),
+				Filename:  f.GetFilename(),
+				Status:    f.GetStatus(),
+				Additions: f.GetAdditions(),
+				Deletions: f.GetDeletions(),
+				Changes:   f.GetChanges(),
+				RawURL:    f.GetRawURL(),
+				Patch:     f.GetPatch(),
+			})
+		}
 
-// GenerateJWT generates a JWT for authenticating as the app
-func (a *App) GenerateJWT() (string, error) {
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"iat": now.Unix(),
-		"exp": now.Add(10 * time.Minute).Unix(),
-		"iss": a.ID,
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(a.PrivateKey)
+	return files, nil
 }
 
-// GetInstallationToken gets an access token for an installation
-func (a *App) GetInstallationToken(installationID int) (*AccessToken, error) {
-	jwt, err := a.GenerateJWT()
+// GetFileContent gets the content of a file at ref.
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, error) {
+	var fileContent *gh.RepositoryContent
+	err := withRateLimitRetry(ctx, func() error {
+		var err error
+		fileContent, _, _, err = c.gh.Repositories.GetContents(ctx, owner, repo, path, &gh.RepositoryContentGetOptions{Ref: ref})
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("github: getting file content: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.github.com/app/installations/%d/access_tokens", installationID)
-	req, _ := http.NewRequest("POST", url, nil)
-	req.Header.Set("Authorization", "Bearer "+jwt)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := a.httpClient.Do(req)
+	content, err := fileContent.GetContent()
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("github: decoding file content: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 201 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to get token: %s", body)
-	}
-
-	var token AccessToken
-	json.NewDecoder(resp.Body).Decode(&token)
-	return &token, nil
+	return content, nil
 }
 
-// Client represents an authenticated GitHub client
-type Client struct {
-	Token      string
-	httpClient *http.Client
-}
+// maxRateLimitRetries bounds how many times withRateLimitRetry will wait out
+// a rate limit and retry before giving up and returning the error to the
+// caller, so a persistently rate-limited installation fails loudly instead
+// of blocking a webhook job indefinitely.
+const maxRateLimitRetries = 3
+
+// withRateLimitRetry calls fn, retrying when it fails with a primary
+// (RateLimitError) or secondary (AbuseRateLimitError) GitHub rate limit
+// error: it sleeps until the primary limit's reset time, or the secondary
+// limit's requested RetryAfter (a minute, if GitHub didn't send one), then
+// tries again. Any other error, or a fn that still fails after
+// maxRateLimitRetries retries (maxRateLimitRetries+1 calls), is returned
+// as-is. A ctx cancellation while
+// waiting aborts the retry immediately.
+func withRateLimitRetry(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
 
-// NewClient creates a new authenticated GitHub client
-func NewClient(token string) *Client {
-	return &Client{
-		Token:      token,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		wait, retryable := rateLimitWait(err)
+		if !retryable || attempt >= maxRateLimitRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 }
 
-// CreateCheckRun creates a new check run
-func (c *Client) CreateCheckRun(owner, repo string, check CheckRun) (*CheckRunResponse, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/check-runs", owner, repo)
-	
-	body, _ := json.Marshal(check)
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+// rateLimitWait reports how long to wait before retrying err, and whether
+// it's a rate limit error at all.
+func rateLimitWait(err error) (time.Duration, bool) {
+	var rl *gh.RateLimitError
+	if errors.As(err, &rl) {
+		if wait := time.Until(rl.Rate.Reset.Time); wait > 0 {
+			return wait, true
+		}
+		return 0, true
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 201 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to create check run: %s", respBody)
+	var abuse *gh.AbuseRateLimitError
+	if errors.As(err, &abuse) {
+		if abuse.RetryAfter != nil {
+			return *abuse.RetryAfter, true
+		}
+		return time.Minute, true
 	}
 
-	var result CheckRunResponse
-	json.NewDecoder(resp.Body).Decode(&result)
-	return &result, nil
+	return 0, false
 }
 
-// UpdateCheckRun updates an existing check run
-func (c *Client) UpdateCheckRun(owner, repo string, checkRunID int64, check CheckRun) error {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/check-runs/%d", owner, repo, checkRunID)
-	
-	body, _ := json.Marshal(check)
-	req, _ := http.NewRequest("PATCH", url, bytes.NewBuffer(body))
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
+// toCreateOpts builds go-github's CreateCheckRunOptions from our CheckRun,
+// which is shaped after the Checks API request body directly (see
+// cmd/server's identical type), so the two need no separate DTO.
+func toCreateOpts(check CheckRun) gh.CreateCheckRunOptions {
+	opts := gh.CreateCheckRunOptions{
+		Name:    check.Name,
+		HeadSHA: check.HeadSHA,
+		Output:  toGHOutput(check.Output),
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update check run: %s", respBody)
+	if check.Status != "" {
+		opts.Status = gh.String(check.Status)
 	}
-
-	return nil
+	if check.Conclusion != "" {
+		opts.Conclusion = gh.String(check.Conclusion)
+	}
+	if ts := parseTimestamp(check.StartedAt); ts != nil {
+		opts.StartedAt = ts
+	}
+	if ts := parseTimestamp(check.CompletedAt); ts != nil {
+		opts.CompletedAt = ts
+	}
+	return opts
 }
 
-// GetPRFiles gets the files changed in a pull request
-func (c *Client) GetPRFiles(owner, repo string, prNumber int) ([]PRFile, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/files", owner, repo, prNumber)
-	
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+func toUpdateOpts(check CheckRun) gh.UpdateCheckRunOptions {
+	opts := gh.UpdateCheckRunOptions{
+		Name:   check.Name,
+		Output: toGHOutput(check.Output),
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to get PR files: %s", respBody)
+	if check.Status != "" {
+		opts.Status = gh.String(check.Status)
 	}
-
-	var files []PRFile
-	json.NewDecoder(resp.Body).Decode(&files)
-	return files, nil
+	if check.Conclusion != "" {
+		opts.Conclusion = gh.String(check.Conclusion)
+	}
+	if ts := parseTimestamp(check.CompletedAt); ts != nil {
+		opts.CompletedAt = ts
+	}
+	return opts
 }
 
-// GetFileContent gets the content of a file
-func (c *Client) GetFileContent(owner, repo, path, ref string) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s", owner, repo, path, ref)
-	
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github.raw+json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
+func toGHOutput(out *CheckOutput) *gh.CheckRunOutput {
+	if out == nil {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("failed to get file: %d", resp.StatusCode)
+	ghOut := &gh.CheckRunOutput{
+		Title:   gh.String(out.Title),
+		Summary: gh.String(out.Summary),
+	}
+	if out.Text != "" {
+		ghOut.Text = gh.String(out.Text)
+	}
+	for _, a := range out.Annotations {
+		ghOut.Annotations = append(ghOut.Annotations, &gh.CheckRunAnnotation{
+			Path:            gh.String(a.Path),
+			StartLine:       gh.Int(a.StartLine),
+			EndLine:         gh.Int(a.EndLine),
+			AnnotationLevel: gh.String(a.AnnotationLevel),
+			Message:         gh.String(a.Message),
+			Title:           gh.String(a.Title),
+		})
 	}
+	return ghOut
+}
 
-	content, _ := io.ReadAll(resp.Body)
-	return string(content), nil
+// parseTimestamp parses an RFC3339 timestamp as produced by
+// time.Now().UTC().Format(time.RFC3339) (see cmd/server's CheckRun
+// construction); an empty or unparsable string yields nil so the field is
+// simply omitted from the request.
+func parseTimestamp(s string) *gh.Timestamp {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &gh.Timestamp{Time: t}
 }
 
-// CheckRun represents a GitHub check run
+// CheckRun represents a GitHub check run.
 type CheckRun struct {
 	Name        string       `json:"name"`
 	HeadSHA     string       `json:"head_sha"`
@@ -230,10 +296,10 @@ type CheckRun struct {
 }
 
 type CheckOutput struct {
-	Title       string        `json:"title"`
-	Summary     string        `json:"summary"`
-	Text        string        `json:"text,omitempty"`
-	Annotations []Annotation  `json:"annotations,omitempty"`
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Text        string       `json:"text,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
 }
 
 type Annotation struct {
@@ -249,7 +315,7 @@ type CheckRunResponse struct {
 	ID int64 `json:"id"`
 }
 
-// PRFile represents a file in a pull request
+// PRFile represents a file in a pull request.
 type PRFile struct {
 	SHA       string `json:"sha"`
 	Filename  string `json:"filename"`
@@ -0,0 +1,114 @@
+package policy
+
+import "fmt"
+
+// EvalContext is the set of signals a compiled trigger expression can
+// reference by name: ai_confidence, ai_confidence_delta, path, lines_changed,
+// review_time, author_trust, and language. Evaluate populates AIConfidence,
+// AIConfidenceDelta, Path, and LinesChanged from real scan/PR data;
+// ReviewTime, AuthorTrust, and Language have no producer yet anywhere in
+// the codebase (no reviewer-timing, contributor-reputation, or per-file
+// language signal is wired up), so they're always zero-valued there. They
+// exist so `vibeguard policy test` can exercise those predicates against a
+// synthetic, caller-supplied context while the real signals are built out.
+type EvalContext struct {
+	AIConfidence      float64
+	AIConfidenceDelta float64 // increase over a baseline scan; 0 when unused
+	Path              string
+	LinesChanged      int
+	ReviewTime        float64 // minutes; test-only, see EvalContext doc
+	AuthorTrust       float64 // 0-1, caller-supplied reputation signal; test-only, see EvalContext doc
+	Language          string  // test-only, see EvalContext doc -- no predicate reads it yet either
+}
+
+// Expr is a compiled trigger expression node. Eval reports whether the
+// expression holds for ctx.
+type Expr interface {
+	Eval(ctx EvalContext) (bool, error)
+}
+
+// andExpr is the boolean AND of two sub-expressions (&&).
+type andExpr struct{ left, right Expr }
+
+func (e *andExpr) Eval(ctx EvalContext) (bool, error) {
+	l, err := e.left.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !l {
+		return false, nil
+	}
+	return e.right.Eval(ctx)
+}
+
+// orExpr is the boolean OR of two sub-expressions (||).
+type orExpr struct{ left, right Expr }
+
+func (e *orExpr) Eval(ctx EvalContext) (bool, error) {
+	l, err := e.left.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	if l {
+		return true, nil
+	}
+	return e.right.Eval(ctx)
+}
+
+// comparisonExpr compares a named numeric field against a literal threshold.
+type comparisonExpr struct {
+	field string
+	op    string
+	value float64
+}
+
+func (e *comparisonExpr) Eval(ctx EvalContext) (bool, error) {
+	v, err := numericField(e.field, ctx)
+	if err != nil {
+		return false, err
+	}
+	switch e.op {
+	case ">":
+		return v > e.value, nil
+	case ">=":
+		return v >= e.value, nil
+	case "<":
+		return v < e.value, nil
+	case "<=":
+		return v <= e.value, nil
+	case "==":
+		return v == e.value, nil
+	case "!=":
+		return v != e.value, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", e.op)
+	}
+}
+
+// pathMatchExpr implements the `path matches "glob"` predicate.
+type pathMatchExpr struct {
+	pattern string
+}
+
+func (e *pathMatchExpr) Eval(ctx EvalContext) (bool, error) {
+	return globMatch(e.pattern, ctx.Path), nil
+}
+
+func numericField(field string, ctx EvalContext) (float64, error) {
+	switch field {
+	case "ai_confidence":
+		return ctx.AIConfidence, nil
+	case "ai_confidence_delta":
+		return ctx.AIConfidenceDelta, nil
+	case "ai_percentage":
+		return ctx.AIConfidence * 100, nil
+	case "lines_changed":
+		return float64(ctx.LinesChanged), nil
+	case "review_time":
+		return ctx.ReviewTime, nil
+	case "author_trust":
+		return ctx.AuthorTrust, nil
+	default:
+		return 0, fmt.Errorf("unknown field %q", field)
+	}
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompiledPolicy is a Policy whose trigger (legacy map or `when` expression)
+// has been compiled into a single Expr, ready for repeated evaluation.
+type CompiledPolicy struct {
+	Name      string
+	Action    string
+	Message   string
+	Paths     []string
+	Reviewers ReviewersConfig
+	expr      Expr
+}
+
+// RuleSet is a compiled Config: every policy's trigger has already been
+// parsed, so Evaluate does no string parsing on the hot path.
+type RuleSet struct {
+	Policies []CompiledPolicy
+}
+
+// Decision is the outcome of one policy matching an EvalContext.
+type Decision struct {
+	Policy  string
+	Action  string
+	Message string
+}
+
+// Compile parses vibeguard.yaml and compiles every policy's trigger
+// expression into a RuleSet. Policies using the legacy `trigger:` map are
+// translated into an equivalent AST so callers only ever deal with one
+// representation.
+func Compile(yamlSrc []byte) (*RuleSet, error) {
+	cfg, err := LoadConfig(yamlSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &RuleSet{}
+	for _, p := range cfg.Policies {
+		expr, err := compileTrigger(p)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		rs.Policies = append(rs.Policies, CompiledPolicy{
+			Name:      p.Name,
+			Action:    p.Action,
+			Message:   p.Message,
+			Paths:     p.Paths,
+			Reviewers: p.Reviewers,
+			expr:      expr,
+		})
+	}
+	return rs, nil
+}
+
+func compileTrigger(p Policy) (Expr, error) {
+	if strings.TrimSpace(p.When) != "" {
+		return parseExpr(p.When)
+	}
+	return convertLegacyTrigger(p.Trigger)
+}
+
+// convertLegacyTrigger turns the old `field: "> 70%"` map into an AST of
+// ANDed comparisons, so legacy vibeguard.yaml files keep working unchanged
+// under the new compiler.
+func convertLegacyTrigger(trigger map[string]string) (Expr, error) {
+	if len(trigger) == 0 {
+		return alwaysTrue{}, nil
+	}
+
+	var combined Expr
+	for field, cond := range trigger {
+		op, threshold, err := parseCondition(cond)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field, err)
+		}
+		if field == "ai_confidence" {
+			// Legacy triggers write this field as a percentage ("> 70%"),
+			// but numericField reads ai_confidence off EvalContext's native
+			// 0-1 scale (ai_percentage is the one numericField multiplies
+			// by 100) -- rescale here so the compiled comparison matches.
+			threshold /= 100
+		}
+		node := Expr(&comparisonExpr{field: field, op: op, value: threshold})
+		if combined == nil {
+			combined = node
+		} else {
+			combined = &andExpr{left: combined, right: node}
+		}
+	}
+	return combined, nil
+}
+
+type alwaysTrue struct{}
+
+func (alwaysTrue) Eval(EvalContext) (bool, error) { return true, nil }
+
+// Evaluate runs every compiled policy against ctx and returns a Decision for
+// each one whose trigger holds, in Config order.
+func (rs *RuleSet) Evaluate(ctx EvalContext) []Decision {
+	var decisions []Decision
+	for _, p := range rs.Policies {
+		if len(p.Paths) > 0 && !pathMatchesAny(ctx.Path, p.Paths) {
+			continue
+		}
+		matched, err := p.expr.Eval(ctx)
+		if err != nil || !matched {
+			continue
+		}
+		decisions = append(decisions, Decision{
+			Policy:  p.Name,
+			Action:  p.Action,
+			Message: p.Message,
+		})
+	}
+	return decisions
+}
@@ -0,0 +1,107 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokAnd
+	tokOr
+	tokOp // > >= < <= == !=
+	tokMatches
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex tokenizes a trigger expression like:
+//
+//	ai_confidence > 0.7 && path matches "src/auth/**" && lines_changed > 100
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	i := 0
+
+	for i < len(runes) {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+
+		case r == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			tokens = append(tokens, token{tokAnd, "&&"})
+			i += 2
+
+		case r == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			tokens = append(tokens, token{tokOr, "||"})
+			i += 2
+
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal in %q", expr)
+			}
+			tokens = append(tokens, token{tokString, string(runes[i+1 : j])})
+			i = j + 1
+
+		case strings.ContainsRune(">=<!", r):
+			j := i + 1
+			if j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, token{tokOp, string(runes[i:j])})
+			i = j
+
+		case unicode.IsDigit(r) || r == '-':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[i:j])})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			if word == "matches" {
+				tokens = append(tokens, token{tokMatches, word})
+			} else {
+				tokens = append(tokens, token{tokIdent, word})
+			}
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression %q", r, expr)
+		}
+	}
+
+	tokens = append(tokens, token{tokEOF, ""})
+	return tokens, nil
+}
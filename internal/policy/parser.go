@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parser is a small recursive-descent parser for the trigger expression
+// language: boolean combinations (&&, ||), comparisons against numeric
+// fields, and `path matches "glob"` predicates. Grammar:
+//
+//	expr       := orExpr
+//	orExpr     := andExpr ( "||" andExpr )*
+//	andExpr    := unary ( "&&" unary )*
+//	unary      := "(" expr ")" | comparison | pathMatch
+//	comparison := IDENT OP NUMBER
+//	pathMatch  := "path" "matches" STRING
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseExpr compiles a trigger expression string into an Expr tree.
+func parseExpr(src string) (Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected trailing token %q in expression %q", p.peek().text, src)
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token     { return p.tokens[p.pos] }
+func (p *parser) advance() token  { t := p.tokens[p.pos]; p.pos++; return t }
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind == tokLParen {
+		p.advance()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("expected ')', got %q", p.peek().text)
+		}
+		p.advance()
+		return expr, nil
+	}
+
+	if p.peek().kind != tokIdent {
+		return nil, fmt.Errorf("expected identifier, got %q", p.peek().text)
+	}
+	field := p.advance().text
+
+	switch p.peek().kind {
+	case tokMatches:
+		p.advance()
+		if p.peek().kind != tokString {
+			return nil, fmt.Errorf("expected string literal after 'matches', got %q", p.peek().text)
+		}
+		pattern := p.advance().text
+		if field != "path" {
+			return nil, fmt.Errorf("'matches' only applies to 'path', got %q", field)
+		}
+		return &pathMatchExpr{pattern: pattern}, nil
+
+	case tokOp:
+		op := p.advance().text
+		if p.peek().kind != tokNumber {
+			return nil, fmt.Errorf("expected number after operator %q, got %q", op, p.peek().text)
+		}
+		num := p.advance().text
+		value, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %w", num, err)
+		}
+		return &comparisonExpr{field: field, op: op, value: value}, nil
+
+	default:
+		return nil, fmt.Errorf("expected operator or 'matches' after %q, got %q", field, p.peek().text)
+	}
+}
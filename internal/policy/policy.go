@@ -0,0 +1,279 @@
+// Package policy evaluates a VibeGuard Config (the parsed vibeguard.yaml)
+// against a file analysis, replacing the Python policy.engine module that
+// the CLI previously invoked via subprocess.
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the parsed form of vibeguard.yaml.
+type Config struct {
+	Version  string   `yaml:"version"`
+	Org      string   `yaml:"org"`
+	Policies []Policy `yaml:"policies"`
+}
+
+// Policy is a single rule from the policies: list. Trigger is the legacy
+// ad-hoc condition map (e.g. `ai_confidence: "> 70%"`); When is the newer
+// CEL-like expression DSL (e.g. `ai_confidence > 0.7 && path matches
+// "src/auth/**"`). A policy may use either; When takes precedence when both
+// are set. See Compile for how the two are reconciled into a single AST.
+type Policy struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Trigger     map[string]string `yaml:"trigger"`
+	When        string            `yaml:"when"`
+	Paths       []string          `yaml:"paths"`
+	Action      string            `yaml:"action"`
+	Message     string            `yaml:"message"`
+	Reviewers   ReviewersConfig   `yaml:"reviewers"`
+}
+
+// ReviewersConfig lists who is required when a policy's action is
+// require_reviewers.
+type ReviewersConfig struct {
+	Teams []string `yaml:"teams"`
+	Users []string `yaml:"users"`
+}
+
+// Analysis is the input to Evaluate: the per-file detection results for a
+// scan or PR, plus aggregate stats. Of the aggregates, Evaluate reads
+// TotalLinesChanged (lines_changed), ReviewTimeMinutes (review_time) and,
+// when Files is empty, MaxAIConfidenceDelta (ai_confidence_delta). The
+// ai_percentage predicate is computed from each file's AIConfidence (see
+// numericField), not from AIPercentage.
+type Analysis struct {
+	Files                []FileAnalysis
+	MaxAIConfidence      float64
+	MaxAIConfidenceDelta float64
+	AIPercentage         float64
+	TotalLinesChanged    int
+	ReviewTimeMinutes    float64
+	SecurityIssues       []string
+}
+
+// FileAnalysis is one file's detection result as seen by the policy engine.
+// AIConfidenceDelta is the increase in AI confidence since a baseline scan
+// (see internal/baseline); it is zero when no baseline is in use.
+type FileAnalysis struct {
+	Path              string
+	AIConfidence      float64
+	AIConfidenceDelta float64
+	LinesChanged      int
+	Status            string
+}
+
+// Violation records a policy whose action blocked the scan.
+type Violation struct {
+	Policy  string
+	Message string
+	Files   []string
+}
+
+// Warning records a policy whose action only warned.
+type Warning struct {
+	Policy  string
+	Message string
+}
+
+// Result is the outcome of evaluating every policy in a Config against an
+// Analysis.
+type Result struct {
+	Allowed           bool
+	Violations        []Violation
+	Warnings          []Warning
+	RequiredReviewers []string
+}
+
+// LoadConfig parses vibeguard.yaml contents into a Config.
+func LoadConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("policy: parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Evaluate runs every policy in cfg against analysis and aggregates the
+// results. Policies whose paths are non-empty only apply to files matching
+// at least one glob; policies with no paths apply repo-wide.
+func Evaluate(cfg *Config, analysis *Analysis) (*Result, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("policy: nil config")
+	}
+	if analysis == nil {
+		return nil, fmt.Errorf("policy: nil analysis")
+	}
+
+	result := &Result{Allowed: true}
+
+	for _, p := range cfg.Policies {
+		matchedFiles, ok, err := matches(p, analysis)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		switch p.Action {
+		case "block", "block_on_commit":
+			result.Allowed = false
+			result.Violations = append(result.Violations, Violation{
+				Policy:  p.Name,
+				Message: p.Message,
+				Files:   matchedFiles,
+			})
+		case "warn":
+			result.Warnings = append(result.Warnings, Warning{
+				Policy:  p.Name,
+				Message: p.Message,
+			})
+		case "require_reviewers":
+			result.RequiredReviewers = append(result.RequiredReviewers, reviewersFor(p.Reviewers)...)
+		default:
+			return nil, fmt.Errorf("policy %q: unknown action %q", p.Name, p.Action)
+		}
+	}
+
+	return result, nil
+}
+
+func reviewersFor(r ReviewersConfig) []string {
+	var out []string
+	for _, t := range r.Teams {
+		out = append(out, "team:"+t)
+	}
+	out = append(out, r.Users...)
+	return out
+}
+
+// matches reports whether p's trigger conditions hold for analysis, and if
+// so, the subset of files that satisfy both the trigger and the path globs.
+// It compiles p's trigger (legacy map or `when` expression) with the same
+// machinery Compile uses, so there's a single evaluator for both
+// representations instead of two that can disagree.
+//
+// The trigger is evaluated once per file, with that file's own Path and
+// AIConfidence/AIConfidenceDelta in EvalContext -- not just once against a
+// repo-wide aggregate -- so a `when` expression's `path matches "..."`
+// predicate actually sees the path it's being evaluated against instead of
+// always seeing "". A trigger that only references PR-wide fields like
+// lines_changed sees the same values for every file. A policy with no files
+// to evaluate against (an empty Analysis) is evaluated once against the
+// PR-wide aggregates, with ai_confidence_delta taken from
+// MaxAIConfidenceDelta.
+func matches(p Policy, analysis *Analysis) ([]string, bool, error) {
+	expr, err := compileTrigger(p)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(analysis.Files) == 0 {
+		triggered, err := expr.Eval(evalContextFromAnalysis(analysis, FileAnalysis{AIConfidenceDelta: analysis.MaxAIConfidenceDelta}))
+		if err != nil {
+			return nil, false, err
+		}
+		return nil, triggered, nil
+	}
+
+	var matchedFiles []string
+	for _, f := range analysis.Files {
+		triggered, err := expr.Eval(evalContextFromAnalysis(analysis, f))
+		if err != nil {
+			return nil, false, err
+		}
+		if !triggered {
+			continue
+		}
+		if len(p.Paths) > 0 && !pathMatchesAny(f.Path, p.Paths) {
+			continue
+		}
+		matchedFiles = append(matchedFiles, f.Path)
+	}
+	return matchedFiles, len(matchedFiles) > 0, nil
+}
+
+func pathMatchesAny(path string, globs []string) bool {
+	for _, g := range globs {
+		if globMatch(g, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch supports filepath.Match plus a "**" wildcard meaning "any depth",
+// since vibeguard.yaml paths like "src/auth/**" don't fit filepath.Match's
+// single-segment "*" semantics. A leading "**/" is dropped, so the rest of
+// the pattern is matched against the whole path or its base name.
+func globMatch(pattern, path string) bool {
+	pattern = strings.TrimPrefix(pattern, "**/")
+	if strings.Contains(pattern, "**") {
+		prefix := strings.SplitN(pattern, "**", 2)[0]
+		return strings.HasPrefix(path, prefix) || strings.Contains(path, strings.TrimSuffix(prefix, "/"))
+	}
+	ok, err := filepath.Match(pattern, path)
+	if err != nil {
+		return false
+	}
+	if ok {
+		return true
+	}
+	// Also try matching just the base name for patterns like "**/auth*".
+	ok, _ = filepath.Match(pattern, filepath.Base(path))
+	return ok
+}
+
+// evalContextFromAnalysis builds the EvalContext for one file's trigger
+// evaluation: Path and AIConfidence/AIConfidenceDelta come from f itself
+// (for an Analysis with no files, matches passes an f that holds only
+// MaxAIConfidenceDelta), while
+// LinesChanged and ReviewTime are PR-wide aggregates from analysis that are
+// the same for every file in one PR. This is the same EvalContext shape
+// Compile's per-file `when` expressions use, so there's a single set of
+// field semantics for both (e.g. ai_confidence and ai_confidence_delta stay
+// on their native 0-1 scale; numericField applies the *100 conversion for
+// ai_percentage).
+func evalContextFromAnalysis(analysis *Analysis, f FileAnalysis) EvalContext {
+	return EvalContext{
+		AIConfidence:      f.AIConfidence,
+		AIConfidenceDelta: f.AIConfidenceDelta,
+		Path:              f.Path,
+		LinesChanged:      analysis.TotalLinesChanged,
+		ReviewTime:        analysis.ReviewTimeMinutes,
+	}
+}
+
+// parseCondition parses strings like "> 70%", "< 2 minutes", "> 100" into an
+// operator and a numeric threshold.
+func parseCondition(cond string) (op string, threshold float64, err error) {
+	cond = strings.TrimSpace(cond)
+	for _, candidate := range []string{">=", "<=", "==", ">", "<"} {
+		if strings.HasPrefix(cond, candidate) {
+			op = candidate
+			cond = strings.TrimSpace(cond[len(candidate):])
+			break
+		}
+	}
+	if op == "" {
+		return "", 0, fmt.Errorf("no operator in condition %q", cond)
+	}
+
+	cond = strings.TrimSuffix(cond, "%")
+	cond = strings.TrimSuffix(cond, " minutes")
+	cond = strings.TrimSuffix(cond, " minute")
+	cond = strings.TrimSpace(cond)
+
+	threshold, parseErr := strconv.ParseFloat(cond, 64)
+	if parseErr != nil {
+		return "", 0, fmt.Errorf("invalid threshold %q: %w", cond, parseErr)
+	}
+	return op, threshold, nil
+}
@@ -0,0 +1,188 @@
+package policy
+
+import "testing"
+
+func TestParseExprComparisonAndBoolean(t *testing.T) {
+	expr, err := parseExpr(`ai_confidence > 0.7 && lines_changed >= 10`)
+	if err != nil {
+		t.Fatalf("parseExpr: %v", err)
+	}
+
+	ok, err := expr.Eval(EvalContext{AIConfidence: 0.8, LinesChanged: 20})
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected expression to match")
+	}
+
+	ok, err = expr.Eval(EvalContext{AIConfidence: 0.5, LinesChanged: 20})
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	if ok {
+		t.Fatal("expected expression not to match when ai_confidence is too low")
+	}
+}
+
+func TestParseExprPathMatches(t *testing.T) {
+	expr, err := parseExpr(`path matches "src/auth/**"`)
+	if err != nil {
+		t.Fatalf("parseExpr: %v", err)
+	}
+
+	if ok, _ := expr.Eval(EvalContext{Path: "src/auth/login.go"}); !ok {
+		t.Fatal("expected path under src/auth/ to match")
+	}
+	if ok, _ := expr.Eval(EvalContext{Path: "src/billing/invoice.go"}); ok {
+		t.Fatal("expected path outside src/auth/ not to match")
+	}
+}
+
+// TestConvertLegacyTriggerAIConfidenceScale is a regression test for the
+// bug where a legacy `ai_confidence: "> 70%"` trigger was compiled to a
+// 0-100-scale threshold but compared against numericField's native 0-1
+// scale, so it never fired.
+func TestConvertLegacyTriggerAIConfidenceScale(t *testing.T) {
+	expr, err := convertLegacyTrigger(map[string]string{"ai_confidence": "> 70%"})
+	if err != nil {
+		t.Fatalf("convertLegacyTrigger: %v", err)
+	}
+
+	ok, err := expr.Eval(EvalContext{AIConfidence: 0.95})
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected ai_confidence 0.95 to satisfy legacy trigger '> 70%'")
+	}
+
+	ok, err = expr.Eval(EvalContext{AIConfidence: 0.5})
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	if ok {
+		t.Fatal("expected ai_confidence 0.5 not to satisfy legacy trigger '> 70%'")
+	}
+}
+
+func TestConvertLegacyTriggerAIPercentageScale(t *testing.T) {
+	expr, err := convertLegacyTrigger(map[string]string{"ai_percentage": "> 70"})
+	if err != nil {
+		t.Fatalf("convertLegacyTrigger: %v", err)
+	}
+
+	if ok, _ := expr.Eval(EvalContext{AIConfidence: 0.8}); !ok {
+		t.Fatal("expected ai_confidence 0.8 (80%) to satisfy ai_percentage '> 70'")
+	}
+}
+
+// TestEvaluateUsesWhenExpression is a regression test for the bug where
+// Evaluate ignored Policy.When entirely, so a when-only policy either never
+// fired or (via the old empty-Trigger-is-always-true fallback) fired
+// unconditionally.
+func TestEvaluateUsesWhenExpression(t *testing.T) {
+	cfg := &Config{
+		Policies: []Policy{
+			{
+				Name:    "rising-ai-content",
+				When:    "ai_confidence_delta > 0.2",
+				Action:  "warn",
+				Message: "AI confidence rose sharply since baseline",
+			},
+		},
+	}
+
+	unchanged := &Analysis{
+		Files:                []FileAnalysis{{Path: "main.go", AIConfidenceDelta: 0.0}},
+		MaxAIConfidenceDelta: 0.0,
+	}
+	result, err := Evaluate(cfg, unchanged)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Fatalf("expected no warnings when ai_confidence_delta is 0, got %+v", result.Warnings)
+	}
+
+	risen := &Analysis{
+		Files:                []FileAnalysis{{Path: "main.go", AIConfidenceDelta: 0.5}},
+		MaxAIConfidenceDelta: 0.5,
+	}
+	result, err = Evaluate(cfg, risen)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected one warning when ai_confidence_delta exceeds 0.2, got %+v", result.Warnings)
+	}
+}
+
+// TestEvaluateWhenPathMatchesSeesRealPath is a regression test for the bug
+// where Evaluate built a single repo-wide EvalContext with Path always "",
+// so a `when` expression's `path matches "..."` predicate was permanently
+// false for every real scan/PR -- exactly the shape of cmdInit's advertised
+// "expression-based trigger" example.
+func TestEvaluateWhenPathMatchesSeesRealPath(t *testing.T) {
+	cfg := &Config{
+		Policies: []Policy{
+			{
+				Name:    "ai-in-auth",
+				When:    `ai_confidence > 0.6 && path matches "src/auth/**"`,
+				Action:  "require_reviewers",
+				Reviewers: ReviewersConfig{
+					Teams: []string{"senior-engineers"},
+				},
+			},
+		},
+	}
+
+	analysis := &Analysis{
+		Files: []FileAnalysis{
+			{Path: "src/auth/login.go", AIConfidence: 0.9},
+			{Path: "src/billing/invoice.go", AIConfidence: 0.9},
+		},
+		MaxAIConfidence: 0.9,
+	}
+
+	result, err := Evaluate(cfg, analysis)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(result.RequiredReviewers) == 0 {
+		t.Fatal("expected the auth-path file to require reviewers")
+	}
+}
+
+func TestEvaluateEmptyLegacyTriggerAlwaysMatches(t *testing.T) {
+	cfg := &Config{
+		Policies: []Policy{
+			{Name: "always-block", Action: "block", Message: "blocked"},
+		},
+	}
+
+	result, err := Evaluate(cfg, &Analysis{})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if result.Allowed {
+		t.Fatal("expected a policy with no trigger/when to always match")
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"src/auth/**", "src/auth/login.go", true},
+		{"src/auth/**", "src/billing/invoice.go", false},
+		{"*.go", "main.go", true},
+		{"*.go", "main.py", false},
+	}
+	for _, c := range cases {
+		if got := globMatch(c.pattern, c.path); got != c.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", c.pattern, c.path, got, c.want)
+		}
+	}
+}
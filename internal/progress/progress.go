@@ -0,0 +1,59 @@
+// Package progress implements a minimal single-line terminal progress bar
+// for long-running scans, so vibeguard scan gives feedback on large
+// repositories instead of blocking silently.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Bar renders "<done>/<total> files (<pct>%) <rate> files/sec ETA <eta>" to
+// out on every Increment, overwriting the previous line. It is safe to call
+// Increment concurrently from multiple goroutines.
+type Bar struct {
+	out   io.Writer
+	total int
+	done  int64
+	start time.Time
+}
+
+// New creates a Bar that tracks progress toward total items.
+func New(out io.Writer, total int) *Bar {
+	return &Bar{out: out, total: total, start: time.Now()}
+}
+
+// Increment records one completed item and redraws the bar.
+func (b *Bar) Increment() {
+	done := atomic.AddInt64(&b.done, 1)
+	b.render(done)
+}
+
+func (b *Bar) render(done int64) {
+	elapsed := time.Since(b.start).Seconds()
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(done) / elapsed
+	}
+
+	pct := 0.0
+	if b.total > 0 {
+		pct = float64(done) / float64(b.total) * 100
+	}
+
+	eta := "?"
+	if rate > 0 && int(done) < b.total {
+		remaining := time.Duration(float64(b.total-int(done))/rate) * time.Second
+		eta = remaining.Round(time.Second).String()
+	}
+
+	fmt.Fprintf(b.out, "\r  %d/%d files (%.0f%%) %.1f files/sec ETA %s   ", done, b.total, pct, rate, eta)
+}
+
+// Finish moves the cursor past the progress line so subsequent output
+// doesn't overwrite it.
+func (b *Bar) Finish() {
+	fmt.Fprintln(b.out)
+}
@@ -0,0 +1,249 @@
+// Package queue persists webhook-triggered analysis jobs so a crashed
+// process or a GitHub webhook redelivery doesn't drop work or produce
+// duplicate check runs. It talks to the database through database/sql
+// against SQLite specifically: the migrations and queries below use `?`
+// placeholders and SQLite's AUTOINCREMENT, neither of which a Postgres
+// driver speaks, so multi-replica deployments aren't supported yet --
+// that needs dialect-aware placeholders and a real driver import, not
+// just a different QueueDSN.
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Status values a Job moves through: queued -> running -> succeeded, or
+// queued -> running -> queued (retry) -> ... -> dead once MaxAttempts is
+// exhausted.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+// DefaultMaxAttempts bounds retries before a job is moved to the dead
+// letter state instead of being retried forever.
+const DefaultMaxAttempts = 5
+
+// Job is one unit of queued work: the raw webhook payload plus enough
+// bookkeeping to retry it with backoff and report status over the API.
+type Job struct {
+	ID            int64
+	DeliveryID    string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	MaxAttempts   int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// migrations are applied in order, tracked by the schema_migrations table,
+// so Migrate is safe to call on every startup. The table that tracks
+// versions is created unconditionally (CREATE TABLE IF NOT EXISTS) before
+// the tracked migrations run.
+var createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`
+
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS jobs (
+		id              INTEGER PRIMARY KEY AUTOINCREMENT,
+		delivery_id     TEXT NOT NULL UNIQUE,
+		payload         BLOB NOT NULL,
+		status          TEXT NOT NULL,
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		max_attempts    INTEGER NOT NULL,
+		last_error      TEXT NOT NULL DEFAULT '',
+		next_attempt_at DATETIME NOT NULL,
+		created_at      DATETIME NOT NULL,
+		updated_at      DATETIME NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS jobs_claim_idx ON jobs (status, next_attempt_at)`,
+}
+
+// Store is a queue backed by a SQL database. It's safe for concurrent use
+// by multiple workers: Claim uses a transaction to atomically pick and
+// mark a job running, so two workers never get the same job.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps an already-open database handle. It does not call
+// Migrate itself -- callers run that once at startup.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate applies any schema_migrations not yet recorded against db.
+func (s *Store) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("queue: creating schema_migrations: %w", err)
+	}
+
+	for i, stmt := range migrations {
+		version := i + 1
+		var exists int
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, version).Scan(&exists); err != nil {
+			return fmt.Errorf("queue: checking migration %d: %w", version, err)
+		}
+		if exists > 0 {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("queue: applying migration %d: %w", version, err)
+		}
+		if _, err := s.db.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+			return fmt.Errorf("queue: recording migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// Enqueue persists a new job for deliveryID. If deliveryID has already been
+// enqueued (a GitHub redelivery), it returns the existing job instead of
+// inserting a duplicate -- this is the idempotency guarantee the webhook
+// handler relies on.
+func (s *Store) Enqueue(ctx context.Context, deliveryID string, payload []byte) (*Job, error) {
+	if existing, err := s.getByDeliveryID(ctx, deliveryID); err == nil {
+		return existing, nil
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("queue: checking for existing delivery %s: %w", deliveryID, err)
+	}
+
+	now := time.Now().UTC()
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO jobs (delivery_id, payload, status, attempts, max_attempts, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, 0, ?, ?, ?, ?)`,
+		deliveryID, payload, StatusQueued, DefaultMaxAttempts, now, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("queue: enqueueing delivery %s: %w", deliveryID, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("queue: reading id for delivery %s: %w", deliveryID, err)
+	}
+	return s.Get(ctx, id)
+}
+
+// Claim atomically picks the oldest queued job whose next_attempt_at has
+// passed and marks it running, or returns (nil, sql.ErrNoRows) if none are
+// ready.
+func (s *Store) Claim(ctx context.Context) (*Job, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("queue: claiming job: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	now := time.Now().UTC()
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM jobs
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY created_at ASC
+		LIMIT 1`, StatusQueued, now).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Guard the UPDATE with the same status check the SELECT used, and
+	// confirm it actually changed a row, rather than relying solely on
+	// this transaction's isolation to prevent two workers from claiming
+	// the same job.
+	res, err := tx.ExecContext(ctx, `UPDATE jobs SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		StatusRunning, now, id, StatusQueued)
+	if err != nil {
+		return nil, fmt.Errorf("queue: marking job %d running: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("queue: checking claim of job %d: %w", id, err)
+	}
+	if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("queue: committing claim of job %d: %w", id, err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+// Complete marks a job succeeded.
+func (s *Store) Complete(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE jobs SET status = ?, updated_at = ? WHERE id = ?`,
+		StatusSucceeded, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("queue: completing job %d: %w", id, err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt. If the job still has attempts remaining it
+// goes back to queued with an exponential backoff delay; otherwise it's
+// moved to the dead letter status and won't be claimed again.
+func (s *Store) Fail(ctx context.Context, id int64, cause error) error {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("queue: loading job %d to fail: %w", id, err)
+	}
+
+	attempts := job.Attempts + 1
+	status := StatusQueued
+	nextAttempt := time.Now().UTC().Add(backoff(attempts))
+	if attempts >= job.MaxAttempts {
+		status = StatusDead
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?`,
+		status, attempts, cause.Error(), nextAttempt, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("queue: recording failure of job %d: %w", id, err)
+	}
+	return nil
+}
+
+// Get loads a job by id.
+func (s *Store) Get(ctx context.Context, id int64) (*Job, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, delivery_id, payload, status, attempts, max_attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM jobs WHERE id = ?`, id)
+	return scanJob(row)
+}
+
+func (s *Store) getByDeliveryID(ctx context.Context, deliveryID string) (*Job, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, delivery_id, payload, status, attempts, max_attempts, last_error, next_attempt_at, created_at, updated_at
+		FROM jobs WHERE delivery_id = ?`, deliveryID)
+	return scanJob(row)
+}
+
+func scanJob(row *sql.Row) (*Job, error) {
+	var j Job
+	err := row.Scan(&j.ID, &j.DeliveryID, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.LastError, &j.NextAttemptAt, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// backoff returns the delay before retry number attempts, doubling from 30s
+// and capping at 30m so a persistently-failing job doesn't retry forever
+// at full speed but also doesn't wait days between attempts.
+func backoff(attempts int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= 30*time.Minute {
+			return 30 * time.Minute
+		}
+	}
+	return d
+}
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s := NewStore(db)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrating test db: %v", err)
+	}
+	return s
+}
+
+func TestEnqueueIsIdempotentPerDelivery(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	first, err := s.Enqueue(ctx, "delivery-1", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	second, err := s.Enqueue(ctx, "delivery-1", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue (redelivery): %v", err)
+	}
+	if first.ID != second.ID {
+		t.Fatalf("expected redelivery to return the same job, got ids %d and %d", first.ID, second.ID)
+	}
+}
+
+// TestClaimDoesNotDoubleClaim checks that a job, once claimed, is not handed
+// out again by a later Claim. The two claims run one after the other, so
+// this does not exercise two Claim transactions racing each other.
+func TestClaimDoesNotDoubleClaim(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	job, err := s.Enqueue(ctx, "delivery-1", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	claimed, err := s.Claim(ctx)
+	if err != nil {
+		t.Fatalf("first Claim: %v", err)
+	}
+	if claimed.ID != job.ID {
+		t.Fatalf("claimed job %d, want %d", claimed.ID, job.ID)
+	}
+
+	if _, err := s.Claim(ctx); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("second Claim = %v, want sql.ErrNoRows (no queued jobs left)", err)
+	}
+}
+
+func TestFailRetriesThenGoesDead(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	job, err := s.Enqueue(ctx, "delivery-1", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	for i := 0; i < job.MaxAttempts; i++ {
+		if err := s.Fail(ctx, job.ID, errors.New("boom")); err != nil {
+			t.Fatalf("Fail (attempt %d): %v", i+1, err)
+		}
+	}
+
+	got, err := s.Get(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != StatusDead {
+		t.Fatalf("status = %q after %d failures, want %q", got.Status, job.MaxAttempts, StatusDead)
+	}
+}
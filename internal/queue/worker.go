@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Handler processes one job's payload. A returned error causes the job to
+// be retried with backoff (see Store.Fail) up to its MaxAttempts.
+type Handler func(ctx context.Context, job *Job) error
+
+// Worker repeatedly claims and runs jobs from a Store until ctx is
+// cancelled. Run multiple Workers (or raise Concurrency) to process jobs in
+// parallel; Claim's transaction keeps them from double-claiming.
+type Worker struct {
+	Store        *Store
+	Handle       Handler
+	Concurrency  int
+	PollInterval time.Duration
+}
+
+// NewWorker returns a Worker with the given concurrency and a 2s poll
+// interval, the defaults cmd/server uses.
+func NewWorker(store *Store, handle Handler, concurrency int) *Worker {
+	return &Worker{Store: store, Handle: handle, Concurrency: concurrency, PollInterval: 2 * time.Second}
+}
+
+// Run blocks until ctx is cancelled, dispatching Concurrency poll loops
+// that each claim and process jobs one at a time.
+func (w *Worker) Run(ctx context.Context) {
+	concurrency := w.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	done := make(chan struct{}, concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			w.poll(ctx)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < concurrency; i++ {
+		<-done
+	}
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.claimAndRun(ctx)
+		}
+	}
+}
+
+func (w *Worker) pollInterval() time.Duration {
+	if w.PollInterval <= 0 {
+		return 2 * time.Second
+	}
+	return w.PollInterval
+}
+
+func (w *Worker) claimAndRun(ctx context.Context) {
+	job, err := w.Store.Claim(ctx)
+	if err != nil {
+		return // sql.ErrNoRows (nothing ready) is the common case, not worth logging
+	}
+
+	if err := w.Handle(ctx, job); err != nil {
+		log.Printf("queue: job %d (delivery %s) failed: %v", job.ID, job.DeliveryID, err)
+		if failErr := w.Store.Fail(ctx, job.ID, err); failErr != nil {
+			log.Printf("queue: recording failure of job %d: %v", job.ID, failErr)
+		}
+		return
+	}
+
+	if err := w.Store.Complete(ctx, job.ID); err != nil {
+		log.Printf("queue: completing job %d: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,103 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Client uploads and fetches scan reports from an S3-compatible bucket.
+type Client struct {
+	mc     *minio.Client
+	bucket string
+}
+
+// NewClient connects to the bucket described by cfg. It works against AWS
+// S3, MinIO, Cloudflare R2, and GCS in S3-compatibility mode -- anything
+// minio-go's client speaks.
+func NewClient(cfg *Config) (*Client, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("reports: storage not configured (endpoint/bucket required)")
+	}
+
+	mc, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reports: connecting to %s: %w", cfg.Endpoint, err)
+	}
+
+	return &Client{mc: mc, bucket: cfg.Bucket}, nil
+}
+
+// objectKey follows the vibeguard/$ORG/$REPO/$COMMIT/<name> convention so
+// reports for the same commit across runs overwrite in place and a bucket
+// listing groups naturally by repo and commit.
+func objectKey(org, repo, commit, name string) string {
+	return fmt.Sprintf("vibeguard/%s/%s/%s/%s", org, repo, commit, name)
+}
+
+// Upload writes the JSON report, SARIF report, and an HTML summary for one
+// commit's scan to the bucket.
+func (c *Client) Upload(ctx context.Context, org, repo, commit string, report *ScanReport, sarifJSON, html []byte) error {
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("reports: marshal report: %w", err)
+	}
+
+	uploads := []struct {
+		name string
+		data []byte
+		ct   string
+	}{
+		{"report.json", reportJSON, "application/json"},
+		{"report.sarif.json", sarifJSON, "application/json"},
+		{"report.html", html, "text/html"},
+	}
+
+	for _, u := range uploads {
+		key := objectKey(org, repo, commit, u.name)
+		_, err := c.mc.PutObject(ctx, c.bucket, key, bytes.NewReader(u.data), int64(len(u.data)),
+			minio.PutObjectOptions{ContentType: u.ct})
+		if err != nil {
+			return fmt.Errorf("reports: upload %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Fetch downloads and parses report.json for one commit.
+func (c *Client) Fetch(ctx context.Context, org, repo, commit string) (*ScanReport, error) {
+	key := objectKey(org, repo, commit, "report.json")
+
+	obj, err := c.mc.GetObject(ctx, c.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("reports: fetch %s: %w", key, err)
+	}
+	defer obj.Close()
+
+	var report ScanReport
+	if err := json.NewDecoder(obj).Decode(&report); err != nil {
+		return nil, fmt.Errorf("reports: decode %s: %w", key, err)
+	}
+	return &report, nil
+}
+
+// Diff fetches the reports for two commits and compares them.
+func (c *Client) Diff(ctx context.Context, org, repo, a, b string) (*DiffResult, error) {
+	reportA, err := c.Fetch(ctx, org, repo, a)
+	if err != nil {
+		return nil, err
+	}
+	reportB, err := c.Fetch(ctx, org, repo, b)
+	if err != nil {
+		return nil, err
+	}
+	return compare(a, reportA, b, reportB), nil
+}
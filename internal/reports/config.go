@@ -0,0 +1,68 @@
+// Package reports persists scan reports to an S3-compatible bucket (AWS S3,
+// MinIO, R2, or GCS in S3-compatibility mode) so teams can retain audit
+// evidence and compare AI-content/policy-violation trends across commits
+// without standing up the VibeGuard API server.
+package reports
+
+import (
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the storage: block of vibeguard.yaml.
+type Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	UseSSL    bool   `yaml:"use_ssl"`
+}
+
+type configFile struct {
+	Storage Config `yaml:"storage"`
+}
+
+// Enabled reports whether enough configuration is present to upload
+// reports. A vibeguard.yaml with no storage: block is the common case and
+// should silently skip uploads rather than error.
+func (c *Config) Enabled() bool {
+	return c != nil && c.Endpoint != "" && c.Bucket != ""
+}
+
+// LoadConfig parses the storage: block out of vibeguard.yaml and applies
+// VIBEGUARD_S3_* environment overrides on top, so CI can inject credentials
+// without committing them to the repo.
+func LoadConfig(yamlSrc []byte) (*Config, error) {
+	var file configFile
+	if len(yamlSrc) > 0 {
+		if err := yaml.Unmarshal(yamlSrc, &file); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := file.Storage
+	applyEnvOverrides(&cfg)
+	return &cfg, nil
+}
+
+func applyEnvOverrides(cfg *Config) {
+	if v := os.Getenv("VIBEGUARD_S3_ENDPOINT"); v != "" {
+		cfg.Endpoint = v
+	}
+	if v := os.Getenv("VIBEGUARD_S3_BUCKET"); v != "" {
+		cfg.Bucket = v
+	}
+	if v := os.Getenv("VIBEGUARD_S3_ACCESS_KEY"); v != "" {
+		cfg.AccessKey = v
+	}
+	if v := os.Getenv("VIBEGUARD_S3_SECRET_KEY"); v != "" {
+		cfg.SecretKey = v
+	}
+	if v := os.Getenv("VIBEGUARD_S3_USE_SSL"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.UseSSL = b
+		}
+	}
+}
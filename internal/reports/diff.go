@@ -0,0 +1,60 @@
+package reports
+
+// DiffResult summarizes how AI-content and policy violations changed
+// between two commits' scan reports.
+type DiffResult struct {
+	CommitA              string      `json:"commit_a"`
+	CommitB              string      `json:"commit_b"`
+	AIPercentageDelta    float64     `json:"ai_percentage_delta"`
+	MaxAIConfidenceDelta float64     `json:"max_ai_confidence_delta"`
+	ViolationCountDelta  int         `json:"violation_count_delta"`
+	FileDeltas           []FileDelta `json:"file_deltas"`
+}
+
+// FileDelta is how one file's AI confidence changed between two reports.
+// Files only present in one report carry a zero confidence for the other
+// side, the same convention baseline drift mode uses.
+type FileDelta struct {
+	Path               string  `json:"path"`
+	AIConfidenceBefore float64 `json:"ai_confidence_before"`
+	AIConfidenceAfter  float64 `json:"ai_confidence_after"`
+	Delta              float64 `json:"delta"`
+}
+
+func compare(commitA string, a *ScanReport, commitB string, b *ScanReport) *DiffResult {
+	before := make(map[string]float64, len(a.Results))
+	for _, f := range a.Results {
+		before[f.Path] = f.AIConfidence
+	}
+
+	seen := make(map[string]bool, len(b.Results))
+	var deltas []FileDelta
+	for _, f := range b.Results {
+		seen[f.Path] = true
+		prev := before[f.Path]
+		if prev == f.AIConfidence {
+			continue
+		}
+		deltas = append(deltas, FileDelta{
+			Path:               f.Path,
+			AIConfidenceBefore: prev,
+			AIConfidenceAfter:  f.AIConfidence,
+			Delta:              f.AIConfidence - prev,
+		})
+	}
+	for path, conf := range before {
+		if seen[path] {
+			continue
+		}
+		deltas = append(deltas, FileDelta{Path: path, AIConfidenceBefore: conf, AIConfidenceAfter: 0, Delta: -conf})
+	}
+
+	return &DiffResult{
+		CommitA:              commitA,
+		CommitB:              commitB,
+		AIPercentageDelta:    b.AIPercentage - a.AIPercentage,
+		MaxAIConfidenceDelta: b.MaxAIConfidence - a.MaxAIConfidence,
+		ViolationCountDelta:  len(b.Violations) - len(a.Violations),
+		FileDeltas:           deltas,
+	}
+}
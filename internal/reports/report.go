@@ -0,0 +1,28 @@
+package reports
+
+// ScanReport is the subset of a VibeGuard scan result that gets persisted
+// and later compared. It mirrors cmd/cli's ScanResult/FileResult/Violation,
+// the same way internal/sarif mirrors them for SARIF conversion.
+type ScanReport struct {
+	FilesScanned    int          `json:"files_scanned"`
+	AIDetected      int          `json:"ai_detected"`
+	MaxAIConfidence float64      `json:"max_ai_confidence"`
+	AIPercentage    float64      `json:"ai_percentage"`
+	Results         []FileResult `json:"results"`
+	Blocked         bool         `json:"blocked"`
+	Violations      []Violation  `json:"violations"`
+}
+
+// FileResult mirrors cmd/cli's FileResult.
+type FileResult struct {
+	Path         string  `json:"path"`
+	AIConfidence float64 `json:"ai_confidence"`
+	Status       string  `json:"status"`
+}
+
+// Violation mirrors cmd/cli's Violation.
+type Violation struct {
+	Policy  string   `json:"policy"`
+	Message string   `json:"message"`
+	Files   []string `json:"files"`
+}
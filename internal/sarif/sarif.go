@@ -0,0 +1,196 @@
+// Package sarif converts a VibeGuard scan result into SARIF 2.1.0, the
+// interchange format understood natively by GitHub Code Scanning, GitLab,
+// the VS Code SARIF Viewer, and Azure DevOps.
+package sarif
+
+import "strconv"
+
+const (
+	schemaURL    = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "vibeguard"
+	toolVersion  = "0.2.0"
+)
+
+// Report is the top-level SARIF log.
+type Report struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a single analysis run (VibeGuard only ever emits one per scan).
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analyzer that produced the run.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver identifies VibeGuard and the rules its results reference: each
+// policy that flagged a reported file, plus the generic ai-generated-code
+// rule when used. Loaded policies that flagged no file are not listed.
+type Driver struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule is a SARIF reportingDescriptor synthesized from a VibeGuard policy.
+type Rule struct {
+	ID               string          `json:"id"`
+	ShortDescription ShortDescription `json:"shortDescription"`
+}
+
+// ShortDescription is the human-readable summary of a Rule.
+type ShortDescription struct {
+	Text string `json:"text"`
+}
+
+// Result is a single SARIF finding, mapped from one VibeGuard FileResult
+// whose AI confidence exceeds aiConfidenceThreshold.
+type Result struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"` // note, warning, error
+	Message    Message                `json:"message"`
+	Locations  []Location             `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Message is a SARIF message object.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location points at the repo-relative file a Result concerns.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// ScanResult is the subset of a VibeGuard scan that ToSARIF needs. It
+// mirrors the ScanResult/FileResult/Violation/Warning types each VibeGuard
+// binary already defines for its own JSON output.
+type ScanResult struct {
+	Results    []FileResult
+	Violations []Violation
+	Warnings   []Warning
+}
+
+// FileResult mirrors cmd/cli's FileResult.
+type FileResult struct {
+	Path         string
+	AIConfidence float64
+	Status       string
+}
+
+// Violation mirrors cmd/cli's Violation.
+type Violation struct {
+	Policy  string
+	Message string
+	Files   []string
+}
+
+// Warning mirrors cmd/cli's Warning.
+type Warning struct {
+	Policy  string
+	Message string
+}
+
+// aiConfidenceThreshold is the bar a FileResult must clear to produce a
+// SARIF result, per the VibeGuard convention used everywhere else (status
+// text, icons, GitHub annotations) for "this file is AI-generated".
+const aiConfidenceThreshold = 0.7
+
+// ToSARIF converts a VibeGuard ScanResult into a SARIF 2.1.0 Report. Every
+// file whose AI confidence exceeds aiConfidenceThreshold becomes a Result;
+// its ruleId is the policy that flagged it (falling back to a generic
+// "ai-generated-code" rule when no specific policy matched that file), and
+// its level is block -> error, warn -> warning.
+func ToSARIF(scan *ScanResult) *Report {
+	policyForFile := make(map[string]string)
+	levelForPolicy := make(map[string]string)
+
+	for _, v := range scan.Violations {
+		levelForPolicy[v.Policy] = "error"
+		for _, f := range v.Files {
+			policyForFile[f] = v.Policy
+		}
+	}
+	for _, w := range scan.Warnings {
+		if _, ok := levelForPolicy[w.Policy]; !ok {
+			levelForPolicy[w.Policy] = "warning"
+		}
+	}
+
+	var results []Result
+	rules := map[string]Rule{}
+
+	for _, f := range scan.Results {
+		if f.AIConfidence <= aiConfidenceThreshold {
+			continue
+		}
+
+		ruleID := policyForFile[f.Path]
+		level := "warning"
+		if ruleID == "" {
+			ruleID = "ai-generated-code"
+		} else if l, ok := levelForPolicy[ruleID]; ok {
+			level = l
+		}
+
+		if _, ok := rules[ruleID]; !ok {
+			desc := "AI-generated code detected above the configured threshold"
+			if ruleID != "ai-generated-code" {
+				desc = ruleID
+			}
+			rules[ruleID] = Rule{ID: ruleID, ShortDescription: ShortDescription{Text: desc}}
+		}
+
+		results = append(results, Result{
+			RuleID: ruleID,
+			Level:  level,
+			Message: Message{
+				Text: "AI-generated code detected (confidence " + formatPercent(f.AIConfidence) + ")",
+			},
+			Locations: []Location{{
+				PhysicalLocation: PhysicalLocation{
+					ArtifactLocation: ArtifactLocation{URI: f.Path},
+				},
+			}},
+			Properties: map[string]interface{}{"aiConfidence": f.AIConfidence},
+		})
+	}
+
+	ruleList := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		ruleList = append(ruleList, r)
+	}
+
+	return &Report{
+		Schema:  schemaURL,
+		Version: sarifVersion,
+		Runs: []Run{{
+			Tool: Tool{Driver: Driver{
+				Name:    toolName,
+				Version: toolVersion,
+				Rules:   ruleList,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func formatPercent(v float64) string {
+	return strconv.Itoa(int(v*100)) + "%"
+}
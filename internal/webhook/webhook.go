@@ -0,0 +1,107 @@
+// Package webhook hardens GitHub webhook delivery handling beyond HMAC
+// signature verification: replay protection via a bounded, TTL'd record of
+// delivery IDs, a check that a delivery's target app matches the one this
+// server is configured for, and a cap on how much body a handler will read
+// before computing the signature at all.
+package webhook
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize and DefaultTTL are the bounds a DeliveryCache uses when
+// constructed with a zero capacity/ttl.
+const (
+	DefaultCacheSize = 10000
+	DefaultTTL       = 24 * time.Hour
+)
+
+// DefaultMaxBodySize matches GitHub's own payload size limit, so legitimate
+// deliveries are never rejected but an attacker can't force the server to
+// buffer an unbounded body before HMAC verification.
+const DefaultMaxBodySize int64 = 25 << 20 // 25 MB
+
+type cacheEntry struct {
+	id   string
+	seen time.Time
+}
+
+// DeliveryCache is a bounded, TTL'd record of X-GitHub-Delivery IDs used to
+// detect replay of a previously-accepted webhook. It evicts the
+// least-recently-seen entry once over capacity, so a sustained flood of
+// distinct deliveries can't grow it unbounded.
+type DeliveryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently seen
+}
+
+// NewDeliveryCache returns a cache holding at most capacity entries (falling
+// back to DefaultCacheSize when <= 0), each considered a duplicate for ttl
+// (falling back to DefaultTTL when <= 0) after it's first seen.
+func NewDeliveryCache(capacity int, ttl time.Duration) *DeliveryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &DeliveryCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Seen reports whether id was already recorded within ttl of now. If not
+// (or if its prior record has expired), it records id as seen at now and
+// returns false.
+func (c *DeliveryCache) Seen(id string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		entry := el.Value.(*cacheEntry)
+		if now.Sub(entry.seen) < c.ttl {
+			c.order.MoveToFront(el)
+			return true
+		}
+		c.order.Remove(el)
+		delete(c.entries, id)
+	}
+
+	el := c.order.PushFront(&cacheEntry{id: id, seen: now})
+	c.entries[id] = el
+	c.evictOverCapacity()
+	return false
+}
+
+func (c *DeliveryCache) evictOverCapacity() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			return
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).id)
+	}
+}
+
+// CheckInstallationTarget reports an error if targetID (the
+// X-GitHub-Hook-Installation-Target-ID header) doesn't match the
+// configured app ID. An empty appID (no app configured) skips the check.
+func CheckInstallationTarget(targetID, appID string) error {
+	if appID == "" {
+		return nil
+	}
+	if targetID != appID {
+		return fmt.Errorf("webhook: installation target %q does not match configured app %q", targetID, appID)
+	}
+	return nil
+}